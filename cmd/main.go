@@ -1,12 +1,15 @@
 package main
 
 import (
+	"cmp"
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
 
 	"github.com/spf13/cobra"
 	"github.com/suer/tf-arm/internal/analyzer"
+	"github.com/suer/tf-arm/internal/imagecache"
 	"github.com/suer/tf-arm/internal/parser"
 	"github.com/suer/tf-arm/internal/reporter"
 )
@@ -15,38 +18,79 @@ var version = "dev"
 var showVersion bool
 var outputFormat string
 var exitCode int
+var imageCacheDir string
+var providerVersions map[string]string
 
 type JSONOutput struct {
 	Summary struct {
 		TotalAnalyzed      int     `json:"total_analyzed"`
 		ARM64Compatible    int     `json:"arm64_compatible"`
 		Migrateable        int     `json:"migrateable"`
+		NotApplicable      int     `json:"not_applicable"`
 		CompatibilityRate  float64 `json:"compatibility_rate"`
 		MigrateablePercent float64 `json:"migrateable_percent"`
+		// Statuses counts analyzed resources by status
+		Statuses map[analyzer.Status]int `json:"statuses"`
+		// Opportunities lists the resources that can migrate, best savings
+		// per effort first
+		Opportunities []Opportunity `json:"opportunities"`
 	} `json:"summary"`
 	Resources []analyzer.ARM64Analysis `json:"resources"`
 }
 
+type Opportunity struct {
+	Address                 string              `json:"address"`
+	RecommendedArch         string              `json:"recommended_arch"`
+	Effort                  int                 `json:"effort"`
+	Confidence              analyzer.Confidence `json:"confidence"`
+	EstimatedSavingsPercent float64             `json:"estimated_savings_percent"`
+	SavingsPerEffort        float64             `json:"savings_per_effort"`
+}
+
 var rootCmd = &cobra.Command{
 	Use:   "tf-arm [state-file]",
 	Short: "Terraform State ARM64 Analyzer",
-	Long: `tf-arm analyzes Terraform state files to identify AWS resources
-that can be migrated to ARM64 architecture for cost optimization.
+	Long: `tf-arm analyzes Terraform state files to identify AWS, Google Cloud
+and Azure resources that can be migrated to ARM64 architecture for cost optimization.
 
 Supported AWS Services:
-  - Amazon EC2 (aws_instance, aws_launch_template)
+  - Amazon EC2 (aws_instance, aws_launch_template, aws_ec2_fleet, aws_spot_fleet_request,
+    aws_ec2_capacity_reservation, aws_ec2_host)
+  - EC2 Image Builder (aws_imagebuilder_image_recipe, aws_imagebuilder_infrastructure_configuration,
+    aws_imagebuilder_image_pipeline)
+  - Amazon EC2 Auto Scaling (aws_autoscaling_group, aws_launch_configuration)
   - AWS Lambda (aws_lambda_function)
-  - Amazon ECS (aws_ecs_task_definition, aws_ecs_service)
-  - Amazon RDS (aws_db_instance, aws_rds_cluster)
-  - Amazon ElastiCache (aws_elasticache_cluster)
+  - Amazon ECS (aws_ecs_task_definition, aws_ecs_service, aws_ecs_capacity_provider,
+    aws_ecs_cluster_capacity_providers)
+  - Amazon RDS (aws_db_instance, aws_rds_cluster, aws_rds_cluster_instance)
+  - Amazon DocumentDB (aws_docdb_cluster_instance)
+  - Amazon Neptune (aws_neptune_cluster_instance)
+  - Amazon ElastiCache (aws_elasticache_cluster, aws_elasticache_replication_group,
+    aws_elasticache_global_replication_group, aws_elasticache_serverless_cache)
   - Amazon MemoryDB (aws_memorydb_cluster)
   - Amazon EKS (aws_eks_node_group)
-  - Amazon EMR (aws_emr_cluster, aws_emrserverless_application)
-  - Amazon OpenSearch (aws_opensearch_domain)
+  - Amazon EMR (aws_emr_cluster, aws_emr_instance_group, aws_emr_instance_fleet,
+    aws_emrserverless_application)
+  - Amazon OpenSearch (aws_opensearch_domain, aws_elasticsearch_domain)
   - Amazon MSK (aws_msk_cluster)
-  - AWS CodeBuild (aws_codebuild_project)
+  - AWS CodeBuild (aws_codebuild_project, aws_codebuild_fleet)
   - Amazon SageMaker (aws_sagemaker_endpoint_configuration)
-  - Amazon GameLift (aws_gamelift_fleet)`,
+  - Amazon GameLift (aws_gamelift_fleet)
+  - AWS Elastic Beanstalk (aws_elastic_beanstalk_environment)
+  - AWS Batch (aws_batch_compute_environment, aws_batch_job_definition)
+
+Supported Kubernetes resources:
+  - Workloads (kubernetes_deployment, kubernetes_daemonset, kubernetes_manifest)
+  - Helm charts (helm_release)
+  - Karpenter NodePool and Provisioner (kubernetes_manifest)
+
+Supported Google Cloud resources:
+  - Compute Engine (google_compute_instance, google_compute_instance_template)
+  - Google Kubernetes Engine (google_container_node_pool)
+
+Supported Azure resources:
+  - Virtual Machines (azurerm_linux_virtual_machine, azurerm_linux_virtual_machine_scale_set)
+  - Azure Kubernetes Service (azurerm_kubernetes_cluster_node_pool)`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if showVersion {
@@ -60,13 +104,15 @@ Supported AWS Services:
 		}
 
 		stateFile := args[0]
-		analyzeStateFile(stateFile, outputFormat, exitCode)
+		analyzeStateFile(stateFile, outputFormat, exitCode, imageCacheDir, providerVersions)
 	},
 }
 
 func init() {
 	rootCmd.Flags().BoolVarP(&showVersion, "version", "v", false, "Show version information")
 	rootCmd.Flags().StringVarP(&outputFormat, "format", "f", "text", "Output format (text or json)")
+	rootCmd.Flags().StringVar(&imageCacheDir, "image-cache", "", "Directory of OCI layouts, docker save tarballs and manifest lists used to check container image architectures")
+	rootCmd.Flags().StringToStringVar(&providerVersions, "provider-version", nil, "Provider versions the state was written with, e.g. aws=5.31.0 (repeatable)")
 	rootCmd.Flags().IntVar(&exitCode, "exit-code", 0, "Exit with specified code when ARM64 compatible resources are found")
 }
 
@@ -78,7 +124,36 @@ func main() {
 }
 
 func canMigrateToARM64(analysis analyzer.ARM64Analysis) bool {
-	return analysis.ARM64Compatible && !analysis.AlreadyUsingARM64
+	status := analysis.Status
+	return status == analyzer.StatusMigratable || status == analyzer.StatusPartiallyMigrated
+}
+
+func isARM64Compatible(analysis analyzer.ARM64Analysis) bool {
+	return analysis.Status == analyzer.StatusAlreadyARM64 || canMigrateToARM64(analysis)
+}
+
+// getOpportunities returns the analyses that can migrate, ordered by savings
+// per effort and then by confidence. Roll-ups are left out in favor of their
+// members.
+func getOpportunities(analyses []analyzer.ARM64Analysis) []analyzer.ARM64Analysis {
+	var opportunities []analyzer.ARM64Analysis
+	for _, analysis := range analyses {
+		if canMigrateToARM64(analysis) && !analysis.RollUp {
+			opportunities = append(opportunities, analysis)
+		}
+	}
+	confidenceRank := map[analyzer.Confidence]int{
+		analyzer.ConfidenceHigh:   0,
+		analyzer.ConfidenceMedium: 1,
+		analyzer.ConfidenceLow:    2,
+	}
+	slices.SortStableFunc(opportunities, func(a, b analyzer.ARM64Analysis) int {
+		if c := cmp.Compare(b.SavingsPerEffort(), a.SavingsPerEffort()); c != 0 {
+			return c
+		}
+		return cmp.Compare(confidenceRank[a.Confidence], confidenceRank[b.Confidence])
+	})
+	return opportunities
 }
 
 func calculateMigrateablePercent(migrateableCount, arm64CompatibleCount int) float64 {
@@ -88,7 +163,7 @@ func calculateMigrateablePercent(migrateableCount, arm64CompatibleCount int) flo
 	return float64(migrateableCount) / float64(arm64CompatibleCount) * 100
 }
 
-func analyzeStateFile(stateFile, format string, exitCode int) {
+func analyzeStateFile(stateFile, format string, exitCode int, imageCacheDir string, providerVersions map[string]string) {
 	// Validate file exists and is accessible
 	if _, err := os.Stat(stateFile); os.IsNotExist(err) {
 		fmt.Printf("Error: State file '%s' does not exist\n", stateFile)
@@ -107,20 +182,54 @@ func analyzeStateFile(stateFile, format string, exitCode int) {
 	var arm64CompatibleCount int
 	var migrateableCount int
 	var totalAnalyzedCount int
+	var notApplicableCount int
 	var analyses []analyzer.ARM64Analysis
+	statusCounts := make(map[analyzer.Status]int)
+
+	ctx := analyzer.NewContext(state)
+	ctx.ProviderVersions = make(map[string]int)
+	for provider, version := range providerVersions {
+		major, err := analyzer.ParseProviderMajor(version)
+		if err != nil {
+			fmt.Printf("Error parsing provider version %s=%s: %v\n", provider, version, err)
+			os.Exit(1)
+		}
+		ctx.ProviderVersions[provider] = major
+	}
+	if imageCacheDir != "" {
+		ctx.Images, err = imagecache.Open(imageCacheDir)
+		if err != nil {
+			fmt.Printf("Error loading image cache: %v\n", err)
+			os.Exit(1)
+		}
+	}
 
 	for _, resource := range state.Resources {
 		if resource.Mode != "managed" {
 			continue
 		}
 
-		analysis := analyzer.AnalyzeResource(resource)
+		analysis := analyzer.AnalyzeResourceWithContext(resource, ctx)
 
 		if analysis.Supported {
-			totalAnalyzedCount++
 			analyses = append(analyses, analysis)
+			// Roll-ups such as Aurora clusters summarize members that are
+			// counted on their own
+			if analysis.RollUp {
+				continue
+			}
+
+			totalAnalyzedCount++
+			statusCounts[analysis.Status]++
 
-			if analysis.ARM64Compatible {
+			// Windows and other resources that can never run on ARM64 are
+			// left out of the compatibility and migration percentages
+			if analysis.Status == analyzer.StatusNotApplicable {
+				notApplicableCount++
+				continue
+			}
+
+			if isARM64Compatible(analysis) {
 				arm64CompatibleCount++
 				// Check if resource is ARM64-compatible but not currently using ARM64
 				if canMigrateToARM64(analysis) {
@@ -137,8 +246,21 @@ func analyzeStateFile(stateFile, format string, exitCode int) {
 		output.Summary.TotalAnalyzed = totalAnalyzedCount
 		output.Summary.ARM64Compatible = arm64CompatibleCount
 		output.Summary.Migrateable = migrateableCount
-		if totalAnalyzedCount > 0 {
-			output.Summary.CompatibilityRate = float64(arm64CompatibleCount) / float64(totalAnalyzedCount) * 100
+		output.Summary.NotApplicable = notApplicableCount
+		output.Summary.Statuses = statusCounts
+		output.Summary.Opportunities = []Opportunity{}
+		for _, analysis := range getOpportunities(analyses) {
+			output.Summary.Opportunities = append(output.Summary.Opportunities, Opportunity{
+				Address:                 analysis.FullAddress,
+				RecommendedArch:         analysis.RecommendedArch,
+				Effort:                  analysis.Effort,
+				Confidence:              analysis.Confidence,
+				EstimatedSavingsPercent: analysis.EstimatedSavingsPercent,
+				SavingsPerEffort:        analysis.SavingsPerEffort(),
+			})
+		}
+		if applicableCount := totalAnalyzedCount - notApplicableCount; applicableCount > 0 {
+			output.Summary.CompatibilityRate = float64(arm64CompatibleCount) / float64(applicableCount) * 100
 		}
 		output.Summary.MigrateablePercent = calculateMigrateablePercent(migrateableCount, arm64CompatibleCount)
 
@@ -161,6 +283,11 @@ func analyzeStateFile(stateFile, format string, exitCode int) {
 		}
 
 		rep.PrintSummary(totalAnalyzedCount, arm64CompatibleCount, migrateableCount)
+		if notApplicableCount > 0 {
+			rep.PrintNotApplicable(notApplicableCount)
+		}
+		rep.PrintStatusCounts(statusCounts)
+		rep.PrintOpportunities(getOpportunities(analyses))
 	}
 
 	if exitCode != 0 && migrateableCount > 0 {
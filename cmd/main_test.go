@@ -22,6 +22,7 @@ func TestCanMigrateToARM64(t *testing.T) {
 		{
 			name: "can migrate - ARM64 compatible but not using ARM64",
 			analysis: analyzer.ARM64Analysis{
+				Status:            analyzer.StatusMigratable,
 				ARM64Compatible:   true,
 				AlreadyUsingARM64: false,
 			},
@@ -30,6 +31,7 @@ func TestCanMigrateToARM64(t *testing.T) {
 		{
 			name: "cannot migrate - already using ARM64",
 			analysis: analyzer.ARM64Analysis{
+				Status:            analyzer.StatusAlreadyARM64,
 				ARM64Compatible:   true,
 				AlreadyUsingARM64: true,
 			},
@@ -38,14 +40,25 @@ func TestCanMigrateToARM64(t *testing.T) {
 		{
 			name: "cannot migrate - not ARM64 compatible",
 			analysis: analyzer.ARM64Analysis{
+				Status:            analyzer.StatusBlocked,
 				ARM64Compatible:   false,
 				AlreadyUsingARM64: false,
 			},
 			expected: false,
 		},
+		{
+			name: "cannot migrate - not applicable",
+			analysis: analyzer.ARM64Analysis{
+				Status:          analyzer.StatusNotApplicable,
+				ARM64Compatible: true,
+				NotApplicable:   true,
+			},
+			expected: false,
+		},
 		{
 			name: "cannot migrate - not compatible and already using ARM64",
 			analysis: analyzer.ARM64Analysis{
+				Status:            analyzer.StatusMisconfigured,
 				ARM64Compatible:   false,
 				AlreadyUsingARM64: true,
 			},
@@ -63,6 +76,27 @@ func TestCanMigrateToARM64(t *testing.T) {
 	}
 }
 
+func TestGetOpportunities(t *testing.T) {
+	analyses := []analyzer.ARM64Analysis{
+		{FullAddress: "aws_instance.web", Status: analyzer.StatusMigratable, Effort: 4, Confidence: analyzer.ConfidenceMedium, EstimatedSavingsPercent: 20},
+		{FullAddress: "aws_instance.arm", Status: analyzer.StatusAlreadyARM64},
+		{FullAddress: "aws_db_instance.main", Status: analyzer.StatusMigratable, Effort: 3, Confidence: analyzer.ConfidenceLow, EstimatedSavingsPercent: 10},
+		{FullAddress: "aws_lambda_function.api", Status: analyzer.StatusMigratable, Effort: 1, Confidence: analyzer.ConfidenceHigh, EstimatedSavingsPercent: 20},
+		{FullAddress: "aws_db_instance.replica", Status: analyzer.StatusMigratable, Effort: 3, Confidence: analyzer.ConfidenceHigh, EstimatedSavingsPercent: 10},
+		{FullAddress: "aws_rds_cluster.main", Status: analyzer.StatusMigratable, Effort: 3, Confidence: analyzer.ConfidenceHigh, RollUp: true},
+	}
+
+	var addresses []string
+	for _, analysis := range getOpportunities(analyses) {
+		addresses = append(addresses, analysis.FullAddress)
+	}
+
+	expected := []string{"aws_lambda_function.api", "aws_instance.web", "aws_db_instance.replica", "aws_db_instance.main"}
+	if strings.Join(addresses, ",") != strings.Join(expected, ",") {
+		t.Errorf("getOpportunities() = %v, want %v", addresses, expected)
+	}
+}
+
 func TestCalculateMigrateablePercent(t *testing.T) {
 	tests := []struct {
 		name                 string
@@ -161,7 +195,7 @@ func TestAnalyzeStateFile_JSONOutput(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	analyzeStateFile(stateFile, "json", 0)
+	analyzeStateFile(stateFile, "json", 0, "", nil)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -189,10 +223,86 @@ func TestAnalyzeStateFile_JSONOutput(t *testing.T) {
 		t.Errorf("Expected Migrateable = 1, got %d", jsonOutput.Summary.Migrateable)
 	}
 
+	if jsonOutput.Summary.Statuses[analyzer.StatusMigratable] != 1 || jsonOutput.Summary.Statuses[analyzer.StatusAlreadyARM64] != 1 {
+		t.Errorf("Expected one migratable and one already_arm64 resource, got %v", jsonOutput.Summary.Statuses)
+	}
+
 	// Verify resources
 	if len(jsonOutput.Resources) != 2 {
 		t.Errorf("Expected 2 resources, got %d", len(jsonOutput.Resources))
 	}
+
+	if resource := jsonOutput.Resources[0]; resource.Architecture != analyzer.ArchitectureX86_64 || len(resource.Findings) == 0 {
+		t.Errorf("Expected x86_64 architecture and findings, got %s %v", resource.Architecture, resource.Findings)
+	}
+
+	if len(jsonOutput.Summary.Opportunities) != 1 || jsonOutput.Summary.Opportunities[0].Address != "aws_instance.example" {
+		t.Errorf("Expected aws_instance.example as the only opportunity, got %v", jsonOutput.Summary.Opportunities)
+	}
+}
+
+func TestAnalyzeStateFile_RollUpNotCounted(t *testing.T) {
+	tempDir := t.TempDir()
+	stateFile := filepath.Join(tempDir, "test.tfstate")
+
+	state := parser.TerraformState{
+		Version: 4,
+		Resources: []parser.TerraformResource{
+			{
+				Mode: "managed",
+				Type: "aws_rds_cluster",
+				Name: "main",
+				Instances: []parser.ResourceInstance{
+					{Attributes: map[string]interface{}{"cluster_identifier": "main", "engine": "aurora-postgresql", "engine_version": "15.4"}},
+				},
+			},
+			{
+				Mode: "managed",
+				Type: "aws_rds_cluster_instance",
+				Name: "writer",
+				Instances: []parser.ResourceInstance{
+					{Attributes: map[string]interface{}{"cluster_identifier": "main", "engine": "aurora-postgresql", "instance_class": "db.r5.large", "writer": true}},
+				},
+			},
+		},
+	}
+
+	stateData, err := json.Marshal(state)
+	if err != nil {
+		t.Fatalf("Failed to marshal state: %v", err)
+	}
+	if err := os.WriteFile(stateFile, stateData, 0644); err != nil {
+		t.Fatalf("Failed to write state file: %v", err)
+	}
+
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	analyzeStateFile(stateFile, "json", 0, "", nil)
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+
+	var jsonOutput JSONOutput
+	if err := json.Unmarshal(buf.Bytes(), &jsonOutput); err != nil {
+		t.Fatalf("Failed to parse JSON output: %v", err)
+	}
+
+	// The cluster is reported but only its instance is counted
+	if len(jsonOutput.Resources) != 2 {
+		t.Errorf("Expected 2 resources, got %d", len(jsonOutput.Resources))
+	}
+	if jsonOutput.Summary.TotalAnalyzed != 1 || jsonOutput.Summary.Migrateable != 1 || jsonOutput.Summary.Statuses[analyzer.StatusMigratable] != 1 {
+		t.Errorf("Expected one migratable resource counted, got total %d, migrateable %d, statuses %v",
+			jsonOutput.Summary.TotalAnalyzed, jsonOutput.Summary.Migrateable, jsonOutput.Summary.Statuses)
+	}
+	if len(jsonOutput.Summary.Opportunities) != 1 || jsonOutput.Summary.Opportunities[0].Address != "aws_rds_cluster_instance.writer" {
+		t.Errorf("Expected aws_rds_cluster_instance.writer as the only opportunity, got %v", jsonOutput.Summary.Opportunities)
+	}
 }
 
 func TestAnalyzeStateFile_TextOutput(t *testing.T) {
@@ -232,7 +342,7 @@ func TestAnalyzeStateFile_TextOutput(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	analyzeStateFile(stateFile, "text", 0)
+	analyzeStateFile(stateFile, "text", 0, "", nil)
 
 	w.Close()
 	os.Stdout = oldStdout
@@ -1,17 +1,44 @@
 package analyzer
 
-import "github.com/suer/tf-arm/internal/parser"
+import (
+	"github.com/suer/tf-arm/internal/imagecache"
+	"github.com/suer/tf-arm/internal/parser"
+)
 
 type ARM64Analysis struct {
-	ResourceType      string
-	ResourceName      string
-	FullAddress       string
+	ResourceType string
+	ResourceName string
+	FullAddress  string
+	// CurrentArch, ARM64Compatible, AlreadyUsingARM64 and Notes predate
+	// Status, Architecture and Findings, and are rendered from them; they stay
+	// in the JSON output for one more release.
 	CurrentArch       string
 	ARM64Compatible   bool
 	AlreadyUsingARM64 bool
 	RecommendedArch   string
 	Notes             string
 	Supported         bool
+	// NotApplicable marks resources that can never run on ARM64, such as
+	// Windows workloads; they are excluded from migration statistics.
+	NotApplicable bool
+	// RollUp marks analyses that summarize member resources analyzed on
+	// their own, such as an Aurora cluster and its instances; they are
+	// reported but left out of the counts and savings, which the members
+	// carry.
+	RollUp bool
+
+	Status       Status
+	Architecture Architecture
+	// ArchitectureDefault is set when Architecture comes from a provider
+	// default rather than an attribute in the state.
+	ArchitectureDefault bool
+	Findings            []Finding
+
+	// Effort scores the work and risk of migrating from 1 to 5; zero when
+	// there is nothing to migrate.
+	Effort                  int
+	Confidence              Confidence
+	EstimatedSavingsPercent float64
 }
 
 type Analyzer interface {
@@ -19,44 +46,149 @@ type Analyzer interface {
 	SupportedType() string
 }
 
+// Context gives analyzers access to the rest of the state so they can
+// cross-check a resource against the resources it references.
+type Context struct {
+	State *parser.TerraformState
+	// Images resolves container image architectures; nil when no image cache
+	// was configured.
+	Images *imagecache.Cache
+	// ProviderVersions maps provider names such as "aws" to their major
+	// version, selecting which attribute paths analyzers read.
+	ProviderVersions map[string]int
+}
+
+func NewContext(state *parser.TerraformState) *Context {
+	return &Context{State: state}
+}
+
+// ImageArchitectures looks up an image in the configured image cache.
+func (c *Context) ImageArchitectures(image string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	return c.Images.Architectures(image)
+}
+
+// FindResources returns the managed resources of the given type in the state.
+func (c *Context) FindResources(resourceType string) []parser.TerraformResource {
+	if c == nil || c.State == nil {
+		return nil
+	}
+
+	var resources []parser.TerraformResource
+	for _, resource := range c.State.Resources {
+		if resource.Mode == "managed" && resource.Type == resourceType {
+			resources = append(resources, resource)
+		}
+	}
+	return resources
+}
+
 func AnalyzeResource(resource parser.TerraformResource) ARM64Analysis {
+	return AnalyzeResourceWithContext(resource, nil)
+}
+
+// AnalyzeResourceWithContext analyzes a resource with access to the state it
+// belongs to. A nil context limits analyzers to the resource's own attributes.
+func AnalyzeResourceWithContext(resource parser.TerraformResource, ctx *Context) ARM64Analysis {
 	var analyzer Analyzer
 
 	switch resource.Type {
 	case "aws_instance":
-		analyzer = &EC2Analyzer{}
+		analyzer = &EC2Analyzer{ctx: ctx}
 	case "aws_launch_template":
-		analyzer = &LaunchTemplateAnalyzer{}
+		analyzer = &LaunchTemplateAnalyzer{ctx: ctx}
+	case "aws_autoscaling_group":
+		analyzer = &AutoScalingGroupAnalyzer{ctx: ctx}
+	case "aws_launch_configuration":
+		analyzer = &LaunchConfigurationAnalyzer{ctx: ctx}
+	case "aws_imagebuilder_image_recipe":
+		analyzer = &ImageRecipeAnalyzer{ctx: ctx}
+	case "aws_imagebuilder_infrastructure_configuration":
+		analyzer = &InfrastructureConfigurationAnalyzer{}
+	case "aws_imagebuilder_image_pipeline":
+		analyzer = &ImagePipelineAnalyzer{ctx: ctx}
+	case "aws_ec2_fleet":
+		analyzer = &EC2FleetAnalyzer{ctx: ctx}
+	case "aws_spot_fleet_request":
+		analyzer = &SpotFleetRequestAnalyzer{ctx: ctx}
+	case "aws_ec2_capacity_reservation":
+		analyzer = &CapacityReservationAnalyzer{ctx: ctx}
+	case "aws_ec2_host":
+		analyzer = &DedicatedHostAnalyzer{ctx: ctx}
 	case "aws_ecs_task_definition":
-		analyzer = &ECSAnalyzer{}
+		analyzer = &ECSAnalyzer{ctx: ctx}
 	case "aws_ecs_service":
-		analyzer = &FargateAnalyzer{}
+		analyzer = &ECSServiceAnalyzer{ctx: ctx}
+	case "aws_ecs_capacity_provider":
+		analyzer = &CapacityProviderAnalyzer{ctx: ctx}
+	case "aws_ecs_cluster_capacity_providers":
+		analyzer = &ClusterCapacityProvidersAnalyzer{ctx: ctx}
 	case "aws_lambda_function":
-		analyzer = &LambdaAnalyzer{}
+		analyzer = &LambdaAnalyzer{ctx: ctx}
 	case "aws_codebuild_project":
-		analyzer = &CodeBuildAnalyzer{}
+		analyzer = &CodeBuildAnalyzer{ctx: ctx}
+	case "aws_codebuild_fleet":
+		analyzer = &CodeBuildFleetAnalyzer{}
 	case "aws_db_instance":
-		analyzer = &RDSAnalyzer{}
+		analyzer = &RDSAnalyzer{ctx: ctx}
 	case "aws_rds_cluster":
-		analyzer = &AuroraAnalyzer{}
+		analyzer = &AuroraAnalyzer{ctx: ctx}
+	case "aws_rds_cluster_instance":
+		analyzer = &AuroraInstanceAnalyzer{ctx: ctx}
+	case "aws_docdb_cluster_instance":
+		analyzer = &DocumentDBInstanceAnalyzer{ctx: ctx}
+	case "aws_neptune_cluster_instance":
+		analyzer = &NeptuneInstanceAnalyzer{ctx: ctx}
 	case "aws_elasticache_cluster":
-		analyzer = &ElastiCacheAnalyzer{}
+		analyzer = &ElastiCacheAnalyzer{ctx: ctx}
+	case "aws_elasticache_replication_group":
+		analyzer = &ElastiCacheReplicationGroupAnalyzer{ctx: ctx}
+	case "aws_elasticache_global_replication_group":
+		analyzer = &ElastiCacheGlobalDatastoreAnalyzer{ctx: ctx}
+	case "aws_elasticache_serverless_cache":
+		analyzer = &ElastiCacheServerlessAnalyzer{}
 	case "aws_memorydb_cluster":
 		analyzer = &MemoryDBAnalyzer{}
 	case "aws_eks_node_group":
 		analyzer = &EKSAnalyzer{}
 	case "aws_emr_cluster":
 		analyzer = &EMRAnalyzer{}
+	case "aws_emr_instance_group":
+		analyzer = &EMRInstanceGroupAnalyzer{ctx: ctx}
+	case "aws_emr_instance_fleet":
+		analyzer = &EMRInstanceFleetAnalyzer{ctx: ctx}
 	case "aws_emrserverless_application":
 		analyzer = &EMRServerlessAnalyzer{}
-	case "aws_opensearch_domain":
+	case "aws_opensearch_domain", "aws_elasticsearch_domain":
 		analyzer = &OpenSearchAnalyzer{}
 	case "aws_msk_cluster":
 		analyzer = &MSKAnalyzer{}
 	case "aws_sagemaker_endpoint_configuration":
-		analyzer = &SageMakerAnalyzer{}
+		analyzer = &SageMakerAnalyzer{ctx: ctx}
 	case "aws_gamelift_fleet":
-		analyzer = &GameLiftAnalyzer{}
+		analyzer = &GameLiftAnalyzer{ctx: ctx}
+	case "aws_elastic_beanstalk_environment":
+		analyzer = &ElasticBeanstalkAnalyzer{}
+	case "aws_batch_compute_environment":
+		analyzer = &BatchComputeEnvironmentAnalyzer{}
+	case "aws_batch_job_definition":
+		analyzer = &BatchJobDefinitionAnalyzer{ctx: ctx}
+	case "kubernetes_deployment", "kubernetes_deployment_v1", "kubernetes_daemonset", "kubernetes_daemon_set_v1":
+		analyzer = &KubernetesWorkloadAnalyzer{ctx: ctx}
+	case "kubernetes_manifest":
+		analyzer = &KubernetesManifestAnalyzer{ctx: ctx}
+	case "helm_release":
+		analyzer = &HelmReleaseAnalyzer{ctx: ctx}
+	case "google_compute_instance", "google_compute_instance_template":
+		analyzer = &GCPComputeInstanceAnalyzer{}
+	case "google_container_node_pool":
+		analyzer = &GKENodePoolAnalyzer{}
+	case "azurerm_linux_virtual_machine", "azurerm_linux_virtual_machine_scale_set":
+		analyzer = &AzureLinuxVirtualMachineAnalyzer{}
+	case "azurerm_kubernetes_cluster_node_pool":
+		analyzer = &AKSNodePoolAnalyzer{}
 	default:
 		return ARM64Analysis{
 			ResourceType:    resource.Type,
@@ -65,11 +197,20 @@ func AnalyzeResource(resource parser.TerraformResource) ARM64Analysis {
 			ARM64Compatible: false,
 			Notes:           "Resource type not supported for ARM64 compatibility check",
 			Supported:       false,
+			Status:          StatusUnsupported,
+			Architecture:    ArchitectureUnknown,
 		}
 	}
 
 	analysis := analyzer.Analyze(resource)
 	analysis.FullAddress = resource.GetFullAddress()
 	analysis.Supported = true
+	if analysis.Status == "" {
+		markUnknown(&analysis, "", "No attribute determining the architecture found in state")
+	}
+	if analysis.Architecture == "" {
+		analysis.Architecture = ArchitectureUnknown
+	}
+	estimateMigration(&analysis)
 	return analysis
 }
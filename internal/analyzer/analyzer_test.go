@@ -169,7 +169,9 @@ func TestAnalyzeResource(t *testing.T) {
 				Instances: []parser.ResourceInstance{
 					{
 						Attributes: map[string]interface{}{
-							"cpu_architecture": "X86_64",
+							"runtime_platform": []any{
+								map[string]any{"cpu_architecture": "X86_64"},
+							},
 						},
 					},
 				},
@@ -206,21 +208,57 @@ func TestAnalyzeResource_AllSupportedTypes(t *testing.T) {
 	supportedTypes := []string{
 		"aws_instance",
 		"aws_launch_template",
+		"aws_autoscaling_group",
+		"aws_launch_configuration",
+		"aws_imagebuilder_image_recipe",
+		"aws_imagebuilder_infrastructure_configuration",
+		"aws_imagebuilder_image_pipeline",
+		"aws_ec2_fleet",
+		"aws_spot_fleet_request",
+		"aws_ec2_capacity_reservation",
+		"aws_ec2_host",
 		"aws_ecs_task_definition",
 		"aws_ecs_service",
+		"aws_ecs_capacity_provider",
+		"aws_ecs_cluster_capacity_providers",
 		"aws_lambda_function",
 		"aws_codebuild_project",
+		"aws_codebuild_fleet",
 		"aws_db_instance",
 		"aws_rds_cluster",
+		"aws_rds_cluster_instance",
+		"aws_docdb_cluster_instance",
+		"aws_neptune_cluster_instance",
 		"aws_elasticache_cluster",
+		"aws_elasticache_replication_group",
+		"aws_elasticache_global_replication_group",
+		"aws_elasticache_serverless_cache",
 		"aws_memorydb_cluster",
 		"aws_eks_node_group",
 		"aws_emr_cluster",
+		"aws_emr_instance_group",
+		"aws_emr_instance_fleet",
 		"aws_emrserverless_application",
 		"aws_opensearch_domain",
+		"aws_elasticsearch_domain",
 		"aws_msk_cluster",
 		"aws_sagemaker_endpoint_configuration",
 		"aws_gamelift_fleet",
+		"aws_elastic_beanstalk_environment",
+		"aws_batch_compute_environment",
+		"aws_batch_job_definition",
+		"kubernetes_deployment",
+		"kubernetes_deployment_v1",
+		"kubernetes_daemonset",
+		"kubernetes_daemon_set_v1",
+		"kubernetes_manifest",
+		"helm_release",
+		"google_compute_instance",
+		"google_compute_instance_template",
+		"google_container_node_pool",
+		"azurerm_linux_virtual_machine",
+		"azurerm_linux_virtual_machine_scale_set",
+		"azurerm_kubernetes_cluster_node_pool",
 	}
 
 	for _, resourceType := range supportedTypes {
@@ -243,6 +281,10 @@ func TestAnalyzeResource_AllSupportedTypes(t *testing.T) {
 				t.Errorf("AnalyzeResource() should support resource type %s", resourceType)
 			}
 
+			if _, ok := getMigrationProfiles()[resourceType]; !ok {
+				t.Errorf("no migration profile for resource type %s", resourceType)
+			}
+
 			if analysis.ResourceType != resourceType {
 				t.Errorf("AnalyzeResource() ResourceType = %v, want %v", analysis.ResourceType, resourceType)
 			}
@@ -0,0 +1,327 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type AutoScalingGroupAnalyzer struct {
+	ctx *Context
+}
+
+func (a *AutoScalingGroupAnalyzer) SupportedType() string {
+	return "aws_autoscaling_group"
+}
+
+func (a *AutoScalingGroupAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		capacity, found := a.resolveCapacity(instance.Attributes)
+		if !found {
+			markUnknown(&analysis, "launch_template", "No launch template or launch configuration found for this Auto Scaling group")
+			continue
+		}
+		applyCapacity(&analysis, capacity)
+	}
+	return analysis
+}
+
+// asgCapacity describes what an Auto Scaling group can launch.
+type asgCapacity struct {
+	// Source names the launch template the group launches from
+	Source string
+	// Attribute is the path of the attribute that selects Source
+	Attribute string
+	// InstanceTypes are the explicit instance types the group can launch
+	InstanceTypes []string
+	// SharedAMI is true when every instance type launches the same AMI, so
+	// the instance types must all share an architecture
+	SharedAMI bool
+	// CPUManufacturers comes from instance_requirements, when used
+	CPUManufacturers []string
+	UsesRequirements bool
+	Findings         []Finding
+}
+
+// resolveCapacity follows launch_template or mixed_instances_policy to the
+// instance types the group launches, resolving launch templates in the state.
+func (a *AutoScalingGroupAnalyzer) resolveCapacity(attributes map[string]any) (asgCapacity, bool) {
+	if spec := getFirstBlock(attributes["launch_template"]); spec != nil {
+		id, _ := spec["id"].(string)
+		name, _ := spec["name"].(string)
+		capacity, _ := a.ctx.resolveLaunchTemplateCapacity(id, name)
+		capacity.Attribute = "launch_template"
+		return capacity, true
+	}
+
+	if name, ok := attributes["launch_configuration"].(string); ok && name != "" {
+		capacity := a.ctx.resolveLaunchConfigurationCapacity(name)
+		capacity.Attribute = "launch_configuration"
+		return capacity, true
+	}
+
+	policy := getFirstBlock(attributes["mixed_instances_policy"])
+	if policy == nil {
+		return asgCapacity{}, false
+	}
+	launchTemplate := getFirstBlock(policy["launch_template"])
+	if launchTemplate == nil {
+		return asgCapacity{}, false
+	}
+
+	spec := getFirstBlock(launchTemplate["launch_template_specification"])
+	id, _ := spec["launch_template_id"].(string)
+	name, _ := spec["launch_template_name"].(string)
+	capacity, _ := a.ctx.resolveLaunchTemplateCapacity(id, name)
+	capacity.Attribute = "mixed_instances_policy.0.launch_template"
+
+	overrides, _ := launchTemplate["override"].([]any)
+	if len(overrides) == 0 {
+		return capacity, true
+	}
+
+	capacity.InstanceTypes = nil
+	for _, override := range overrides {
+		overrideMap, ok := override.(map[string]any)
+		if !ok {
+			continue
+		}
+		if instanceType, ok := overrideMap["instance_type"].(string); ok && instanceType != "" {
+			capacity.InstanceTypes = append(capacity.InstanceTypes, instanceType)
+		}
+		if requirements := getFirstBlock(overrideMap["instance_requirements"]); requirements != nil {
+			capacity.UsesRequirements = true
+			capacity.CPUManufacturers = append(capacity.CPUManufacturers, getStringList(requirements["cpu_manufacturers"])...)
+		}
+		// Overrides with their own launch template can pair each instance
+		// type with an AMI of the matching architecture
+		if getFirstBlock(overrideMap["launch_template_specification"]) != nil {
+			capacity.SharedAMI = false
+		}
+	}
+	return capacity, true
+}
+
+// resolveLaunchTemplateCapacity finds an aws_launch_template in the state by
+// ID or name.
+func (c *Context) resolveLaunchTemplateCapacity(id, name string) (asgCapacity, bool) {
+	for _, template := range c.FindResources("aws_launch_template") {
+		for _, instance := range template.Instances {
+			templateID, _ := instance.Attributes["id"].(string)
+			templateName, _ := instance.Attributes["name"].(string)
+			if (id == "" || templateID != id) && (name == "" || templateName != name) {
+				continue
+			}
+
+			capacity := asgCapacity{Source: template.GetFullAddress(), SharedAMI: true}
+			if instanceType, ok := instance.Attributes["instance_type"].(string); ok && instanceType != "" {
+				capacity.InstanceTypes = []string{instanceType}
+			}
+			if requirements := getFirstBlock(instance.Attributes["instance_requirements"]); requirements != nil {
+				capacity.UsesRequirements = true
+				capacity.CPUManufacturers = getStringList(requirements["cpu_manufacturers"])
+			}
+			return capacity, true
+		}
+	}
+
+	reference := id
+	if reference == "" {
+		reference = name
+	}
+	return asgCapacity{
+		Source:    reference,
+		SharedAMI: true,
+		Findings:  []Finding{newFinding(FindingMissingAttribute, "", "Launch template "+reference+" not found in state")},
+	}, false
+}
+
+// resolveLaunchConfigurationCapacity finds an aws_launch_configuration in the
+// state by name.
+func (c *Context) resolveLaunchConfigurationCapacity(name string) asgCapacity {
+	for _, configuration := range c.FindResources("aws_launch_configuration") {
+		for _, instance := range configuration.Instances {
+			if configurationName, _ := instance.Attributes["name"].(string); configurationName != name {
+				continue
+			}
+
+			capacity := asgCapacity{Source: configuration.GetFullAddress(), SharedAMI: true}
+			if instanceType, ok := instance.Attributes["instance_type"].(string); ok && instanceType != "" {
+				capacity.InstanceTypes = []string{instanceType}
+				if !isARM64InstanceType(instanceType) {
+					capacity.Findings = []Finding{newFinding(FindingPrerequisite, "", "Launch configurations cannot be modified; replace "+capacity.Source+" with a launch template")}
+				}
+			}
+			return capacity
+		}
+	}
+
+	return asgCapacity{
+		Source:    name,
+		SharedAMI: true,
+		Findings:  []Finding{newFinding(FindingMissingAttribute, "", "Launch configuration "+name+" not found in state")},
+	}
+}
+
+// applyCapacity records the Graviton decision for the instance types and
+// instance requirements a group of instances can launch with.
+func applyCapacity(analysis *ARM64Analysis, capacity asgCapacity) {
+	var armTypes, x86Types, recommended, missing []string
+	for _, instanceType := range capacity.InstanceTypes {
+		switch {
+		case isARM64InstanceType(instanceType):
+			armTypes = append(armTypes, instanceType)
+			if !slices.Contains(recommended, instanceType) {
+				recommended = append(recommended, instanceType)
+			}
+		case hasARM64Alternative(instanceType):
+			x86Types = append(x86Types, instanceType)
+			if alternative := getARM64Alternative(instanceType); !slices.Contains(recommended, alternative) {
+				recommended = append(recommended, alternative)
+			}
+		default:
+			x86Types = append(x86Types, instanceType)
+			missing = append(missing, instanceType)
+		}
+	}
+
+	gravitonRequirements := slices.Contains(capacity.CPUManufacturers, "amazon-web-services")
+	otherRequirements := slices.ContainsFunc(capacity.CPUManufacturers, func(m string) bool { return m != "amazon-web-services" })
+
+	switch {
+	case len(armTypes) > 0 && len(x86Types) > 0 && capacity.SharedAMI:
+		analysis.setArchitecture(ArchitectureMixed)
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, capacity.Attribute, fmt.Sprintf("overrides mix arm64 (%s) and x86_64 (%s) instance types with a single AMI from %s",
+			strings.Join(armTypes, ", "), strings.Join(x86Types, ", "), capacity.Source))
+	case gravitonRequirements && otherRequirements && capacity.SharedAMI:
+		analysis.setArchitecture(ArchitectureMixed)
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, capacity.Attribute, "instance_requirements cpu_manufacturers mixes Graviton and x86_64 with a single AMI from "+capacity.Source)
+	case len(x86Types) == 0 && (len(armTypes) > 0 || gravitonRequirements):
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, capacity.Attribute, "Already using ARM64 instance types")
+	case len(x86Types) > 0 && len(missing) == len(x86Types):
+		analysis.decide(StatusBlocked, FindingNoARM64Option, capacity.Attribute, "No ARM64 compatible instance type available for "+strings.Join(missing, ", "))
+	case len(x86Types) > 0:
+		analysis.RecommendedArch = strings.Join(recommended, ", ")
+		if len(armTypes) > 0 {
+			analysis.setArchitecture(ArchitectureMixed)
+			analysis.decide(StatusPartiallyMigrated, FindingPartiallyMigrated, capacity.Attribute, "Can migrate to ARM64 instance types: "+analysis.RecommendedArch)
+		} else {
+			analysis.decide(StatusMigratable, FindingMigratable, capacity.Attribute, "Can migrate to ARM64 instance types: "+analysis.RecommendedArch)
+		}
+		analysis.addFinding(FindingUnverified, capacity.Attribute, "Requires an arm64 AMI in "+capacity.Source)
+		if len(missing) > 0 {
+			analysis.addFinding(FindingNoARM64Option, capacity.Attribute, "No direct ARM64 alternative for "+strings.Join(missing, ", "))
+		}
+	case capacity.UsesRequirements:
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusMigratable, FindingMigratable, capacity.Attribute, "Can set instance_requirements cpu_manufacturers = [\"amazon-web-services\"] with an arm64 AMI in "+capacity.Source)
+	default:
+		markUnknown(analysis, capacity.Attribute, "No instance types found in "+capacity.Source)
+	}
+
+	for _, finding := range capacity.Findings {
+		if finding.Attribute == "" {
+			finding.Attribute = capacity.Attribute
+		}
+		analysis.recordFinding(finding)
+	}
+}
+
+type LaunchConfigurationAnalyzer struct {
+	ctx *Context
+}
+
+func (a *LaunchConfigurationAnalyzer) SupportedType() string {
+	return "aws_launch_configuration"
+}
+
+// Analyze applies the EC2 instance type logic. Launch configurations are
+// immutable and deprecated, so the migration path is always a new launch
+// template rather than an in-place change.
+func (a *LaunchConfigurationAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		instanceType, ok := instance.Attributes["instance_type"].(string)
+		if !ok {
+			continue
+		}
+		analysis.setArchitecture(getArchFromInstanceType(instanceType))
+
+		if isARM64InstanceType(instanceType) {
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "instance_type", "Already using ARM64 instance type")
+			analysis.addFinding(FindingNote, "", "Launch configurations are deprecated; consider replacing with a launch template")
+		} else if hasARM64Alternative(instanceType) {
+			analysis.RecommendedArch = getARM64Alternative(instanceType)
+			analysis.decide(StatusMigratable, FindingMigratable, "instance_type", "Replace with launch template using "+analysis.RecommendedArch+" and an arm64 AMI")
+		} else {
+			analysis.decide(StatusBlocked, FindingNoARM64Option, "instance_type", "No ARM64 compatible instance type available")
+		}
+
+		name, _ := instance.Attributes["name"].(string)
+		if groups := a.findAutoScalingGroups(name); len(groups) > 0 {
+			analysis.addFinding(FindingNote, "", "Used by "+strings.Join(groups, ", "))
+		}
+	}
+	return analysis
+}
+
+// findAutoScalingGroups returns the addresses of the Auto Scaling groups that
+// launch from the named launch configuration.
+func (a *LaunchConfigurationAnalyzer) findAutoScalingGroups(name string) []string {
+	if name == "" {
+		return nil
+	}
+
+	var groups []string
+	for _, group := range a.ctx.FindResources("aws_autoscaling_group") {
+		for _, instance := range group.Instances {
+			if configuration, _ := instance.Attributes["launch_configuration"].(string); configuration == name {
+				groups = append(groups, group.GetFullAddress())
+				break
+			}
+		}
+	}
+	return groups
+}
+
+// getFirstBlock returns the first element of a nested block list.
+func getFirstBlock(value any) map[string]any {
+	blocks, ok := value.([]any)
+	if !ok || len(blocks) == 0 {
+		return nil
+	}
+	block, _ := blocks[0].(map[string]any)
+	return block
+}
+
+func getStringList(value any) []string {
+	list, ok := value.([]any)
+	if !ok {
+		return nil
+	}
+
+	var result []string
+	for _, item := range list {
+		if str, ok := item.(string); ok {
+			result = append(result, str)
+		}
+	}
+	return result
+}
@@ -0,0 +1,262 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestAutoScalingGroupAnalyzer_Analyze(t *testing.T) {
+	launchConfiguration := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_launch_configuration",
+		Name: "legacy",
+		Instances: []parser.ResourceInstance{
+			{
+				Attributes: map[string]interface{}{
+					"name":          "legacy-20240101",
+					"instance_type": "c5.large",
+				},
+			},
+		},
+	}
+
+	launchTemplate := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_launch_template",
+		Name: "web",
+		Instances: []parser.ResourceInstance{
+			{
+				Attributes: map[string]interface{}{
+					"id":            "lt-0123456789abcdef0",
+					"name":          "web",
+					"instance_type": "m5.large",
+					"image_id":      "ami-0123456789abcdef0",
+				},
+			},
+		},
+	}
+
+	mixedPolicy := func(overrides ...any) []any {
+		return []any{
+			map[string]any{
+				"launch_template": []any{
+					map[string]any{
+						"launch_template_specification": []any{
+							map[string]any{"launch_template_id": "lt-0123456789abcdef0"},
+						},
+						"override": overrides,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		attributes      map[string]interface{}
+		expectARM64     bool
+		expectUsing     bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name: "launch template resolved from state",
+			attributes: map[string]interface{}{
+				"launch_template": []any{map[string]any{"id": "lt-0123456789abcdef0"}},
+			},
+			expectARM64:     true,
+			expectRecommend: "m7g.large",
+			expectNotes:     "Requires an arm64 AMI in aws_launch_template.web",
+		},
+		{
+			name: "x86 overrides get Graviton recommendations",
+			attributes: map[string]interface{}{
+				"mixed_instances_policy": mixedPolicy(
+					map[string]any{"instance_type": "c5.xlarge"},
+					map[string]any{"instance_type": "m5.xlarge"},
+				),
+			},
+			expectARM64:     true,
+			expectRecommend: "c7g.xlarge, m7g.xlarge",
+			expectNotes:     "Can migrate to ARM64 instance types",
+		},
+		{
+			name: "mixed overrides with a single AMI are misconfigured",
+			attributes: map[string]interface{}{
+				"mixed_instances_policy": mixedPolicy(
+					map[string]any{"instance_type": "m5.large"},
+					map[string]any{"instance_type": "m7g.large"},
+				),
+			},
+			expectARM64: false,
+			expectNotes: "Misconfigured: overrides mix arm64 (m7g.large) and x86_64 (m5.large) instance types with a single AMI from aws_launch_template.web",
+		},
+		{
+			name: "mixed overrides with per-override launch templates",
+			attributes: map[string]interface{}{
+				"mixed_instances_policy": mixedPolicy(
+					map[string]any{"instance_type": "m5.large"},
+					map[string]any{
+						"instance_type": "m7g.large",
+						"launch_template_specification": []any{
+							map[string]any{"launch_template_id": "lt-0fedcba9876543210"},
+						},
+					},
+				),
+			},
+			expectARM64:     true,
+			expectRecommend: "m7g.large",
+			expectNotes:     "Can migrate to ARM64 instance types",
+		},
+		{
+			name: "Graviton instance requirements",
+			attributes: map[string]interface{}{
+				"mixed_instances_policy": mixedPolicy(
+					map[string]any{
+						"instance_requirements": []any{
+							map[string]any{"cpu_manufacturers": []any{"amazon-web-services"}},
+						},
+					},
+				),
+			},
+			expectARM64: true,
+			expectUsing: true,
+			expectNotes: "Already using ARM64",
+		},
+		{
+			name: "x86 instance requirements",
+			attributes: map[string]interface{}{
+				"mixed_instances_policy": mixedPolicy(
+					map[string]any{
+						"instance_requirements": []any{
+							map[string]any{"cpu_manufacturers": []any{"intel", "amd"}},
+						},
+					},
+				),
+			},
+			expectARM64: true,
+			expectNotes: `Can set instance_requirements cpu_manufacturers = ["amazon-web-services"]`,
+		},
+		{
+			name: "launch configuration resolved from state",
+			attributes: map[string]interface{}{
+				"launch_configuration": "legacy-20240101",
+			},
+			expectARM64:     true,
+			expectRecommend: "c7g.large",
+			expectNotes:     "replace aws_launch_configuration.legacy with a launch template",
+		},
+		{
+			name: "launch template missing from state",
+			attributes: map[string]interface{}{
+				"launch_template": []any{map[string]any{"name": "other"}},
+			},
+			expectARM64: false,
+			expectNotes: "Launch template other not found in state",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &parser.TerraformState{Version: 4, Resources: []parser.TerraformResource{launchTemplate, launchConfiguration}}
+			analyzer := &AutoScalingGroupAnalyzer{ctx: NewContext(state)}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type:      "aws_autoscaling_group",
+				Name:      "web",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
+
+func TestLaunchConfigurationAnalyzer_Analyze(t *testing.T) {
+	group := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_autoscaling_group",
+		Name: "workers",
+		Instances: []parser.ResourceInstance{
+			{
+				Attributes: map[string]interface{}{
+					"launch_configuration": "workers-20240101",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		instanceType    string
+		expectARM64     bool
+		expectUsing     bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name:            "x86 instance type",
+			instanceType:    "m5.xlarge",
+			expectARM64:     true,
+			expectRecommend: "m7g.xlarge",
+			expectNotes:     "Replace with launch template using m7g.xlarge and an arm64 AMI | Used by aws_autoscaling_group.workers",
+		},
+		{
+			name:         "ARM64 instance type",
+			instanceType: "m7g.xlarge",
+			expectARM64:  true,
+			expectUsing:  true,
+			expectNotes:  "Already using ARM64 instance type",
+		},
+		{
+			name:         "no ARM64 alternative",
+			instanceType: "p3.2xlarge",
+			expectARM64:  false,
+			expectNotes:  "No ARM64 compatible instance type available",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &parser.TerraformState{Version: 4, Resources: []parser.TerraformResource{group}}
+			analyzer := &LaunchConfigurationAnalyzer{ctx: NewContext(state)}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type: "aws_launch_configuration",
+				Name: "workers",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"name":          "workers-20240101",
+							"instance_type": tt.instanceType,
+						},
+					},
+				},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type AzureLinuxVirtualMachineAnalyzer struct{}
+
+func (a *AzureLinuxVirtualMachineAnalyzer) SupportedType() string {
+	return "azurerm_linux_virtual_machine"
+}
+
+// Analyze handles azurerm_linux_virtual_machine and
+// azurerm_linux_virtual_machine_scale_set, which name the VM size "size" and
+// "sku" respectively.
+func (a *AzureLinuxVirtualMachineAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	sizeAttribute := "size"
+	if resource.Type == "azurerm_linux_virtual_machine_scale_set" {
+		sizeAttribute = "sku"
+	}
+
+	for _, instance := range resource.Instances {
+		size, ok := instance.Attributes[sizeAttribute].(string)
+		if !ok || size == "" {
+			continue
+		}
+		applyAzureVMSize(&analysis, sizeAttribute, size, getAzureImage(instance.Attributes))
+	}
+	return analysis
+}
+
+type AKSNodePoolAnalyzer struct{}
+
+func (a *AKSNodePoolAnalyzer) SupportedType() string {
+	return "azurerm_kubernetes_cluster_node_pool"
+}
+
+func (a *AKSNodePoolAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		if osType, _ := instance.Attributes["os_type"].(string); isWindowsOperatingSystem(osType) {
+			markNotApplicableWindows(&analysis, osType)
+			continue
+		}
+
+		vmSize, ok := instance.Attributes["vm_size"].(string)
+		if !ok || vmSize == "" {
+			continue
+		}
+
+		// AKS picks the arm64 node image for the VM size itself
+		applyAzureVMSize(&analysis, "vm_size", vmSize, azureImage{})
+		if analysis.canMigrate() {
+			analysis.addFinding(FindingUnverified, "", "AKS does not taint Arm nodes; workloads scheduled here need arm64 images or a kubernetes.io/arch node selector")
+		}
+	}
+	return analysis
+}
+
+// azureImage is the image a VM boots: a marketplace image from
+// source_image_reference or a custom image from source_image_id.
+type azureImage struct {
+	Publisher string
+	Offer     string
+	SKU       string
+	ID        string
+}
+
+func (i azureImage) String() string {
+	if i.ID != "" {
+		return i.ID
+	}
+	return i.Publisher + ":" + i.Offer + ":" + i.SKU
+}
+
+// Arch infers the architecture of a marketplace image from its SKU, e.g.
+// "22_04-lts-arm64". Custom images return ArchitectureUnknown.
+func (i azureImage) Arch() Architecture {
+	switch {
+	case i.SKU == "":
+		return ArchitectureUnknown
+	case strings.Contains(strings.ToLower(i.SKU), "arm64"):
+		return ArchitectureARM64
+	default:
+		return ArchitectureX86_64
+	}
+}
+
+func getAzureImage(attributes map[string]any) azureImage {
+	var image azureImage
+	image.ID, _ = attributes["source_image_id"].(string)
+	if reference := getFirstBlock(attributes["source_image_reference"]); reference != nil {
+		image.Publisher, _ = reference["publisher"].(string)
+		image.Offer, _ = reference["offer"].(string)
+		image.SKU, _ = reference["sku"].(string)
+	}
+	return image
+}
+
+// applyAzureVMSize records the decision for a VM size and the image it boots.
+// A zero image skips the image check.
+func applyAzureVMSize(analysis *ARM64Analysis, attribute, size string, image azureImage) {
+	imageArch := image.Arch()
+
+	if isARM64AzureVMSize(size) {
+		analysis.setArchitecture(ArchitectureARM64)
+		if imageArch == ArchitectureX86_64 {
+			analysis.decide(StatusMisconfigured, FindingMisconfigured, "source_image_reference", "Arm size "+size+" boots x86_64 image "+image.String())
+			return
+		}
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, attribute, "Already using Arm-based VM size")
+		return
+	}
+
+	analysis.setArchitecture(ArchitectureX86_64)
+	if imageArch == ArchitectureARM64 {
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, "source_image_reference", "x86_64 size "+size+" boots arm64 image "+image.String())
+		return
+	}
+	alternative := getAzureArmVMSize(size)
+	if alternative == "" {
+		analysis.decide(StatusBlocked, FindingNoARM64Option, attribute, "No Arm-based VM size available for "+size)
+		return
+	}
+
+	analysis.RecommendedArch = alternative
+	analysis.decide(StatusMigratable, FindingMigratable, attribute, fmt.Sprintf("Can migrate to Arm-based VM size %s", alternative))
+	switch {
+	case imageArch == ArchitectureX86_64:
+		analysis.addFinding(FindingUnverified, "source_image_reference", fmt.Sprintf("Requires an arm64 image: use sku %s of %s:%s", getAzureARM64ImageSKU(image.SKU), image.Publisher, image.Offer))
+	case image.ID != "":
+		analysis.addFinding(FindingUnverified, "source_image_id", "Requires an arm64 image in place of "+image.ID)
+	}
+}
+
+var (
+	azureArmVMSizePattern = regexp.MustCompile(`^Standard_[A-Z]+\d+p[a-z]*_v\d+$`)
+	azureX86VMSizePattern = regexp.MustCompile(`^Standard_([DE])(\d+)a?(l?)(d?)s?_v[345]$`)
+)
+
+// isARM64AzureVMSize reports whether a size belongs to an Arm-based series,
+// which Azure marks with a "p" after the vCPU count, e.g. "Standard_D4ps_v5".
+func isARM64AzureVMSize(size string) bool {
+	return azureArmVMSizePattern.MatchString(size)
+}
+
+// getAzureArmVMSeriesSizes lists the vCPU counts offered by the Ampere Altra
+// based Dpsv5 and Epsv5 families and their local disk and low memory
+// variants.
+func getAzureArmVMSeriesSizes() map[string][]int {
+	return map[string][]int{
+		"D": {2, 4, 8, 16, 32, 48, 64},
+		"E": {2, 4, 8, 16, 20, 32},
+	}
+}
+
+// getAzureArmVMSize maps a Dsv3-v5 or Esv3-v5 style size, including the AMD,
+// local disk and low memory variants, to the smallest Ampere-based size of the
+// same family with at least as many vCPUs, e.g. "Standard_D4s_v5" ->
+// "Standard_D4ps_v5" and "Standard_D4lds_v5" -> "Standard_D4plds_v5".
+func getAzureArmVMSize(size string) string {
+	match := azureX86VMSizePattern.FindStringSubmatch(size)
+	if match == nil {
+		return ""
+	}
+	family, lowMemory, localDisk := match[1], match[3], match[4]
+	vcpus, err := strconv.Atoi(match[2])
+	if err != nil {
+		return ""
+	}
+
+	for _, armVCPUs := range getAzureArmVMSeriesSizes()[family] {
+		if armVCPUs >= vcpus {
+			return fmt.Sprintf("Standard_%s%dp%s%ss_v5", family, armVCPUs, lowMemory, localDisk)
+		}
+	}
+	return ""
+}
+
+// getAzureARM64ImageSKU suggests the arm64 SKU of a marketplace image, which
+// publishers name after the x86_64 SKU, e.g. "22_04-lts-gen2" ->
+// "22_04-lts-arm64".
+func getAzureARM64ImageSKU(sku string) string {
+	sku = strings.TrimSuffix(sku, "-gen2")
+	sku = strings.TrimSuffix(sku, "-gen1")
+	return sku + "-arm64"
+}
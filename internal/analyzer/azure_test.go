@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestAzureAnalyzers_Analyze(t *testing.T) {
+	ubuntu := func(sku string) []any {
+		return []any{
+			map[string]any{
+				"publisher": "Canonical",
+				"offer":     "0001-com-ubuntu-server-jammy",
+				"sku":       sku,
+				"version":   "latest",
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		resourceType    string
+		attributes      map[string]interface{}
+		expectARM64     bool
+		expectAlready   bool
+		expectNA        bool
+		expectArch      string
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name:         "Dsv5 VM with Ubuntu",
+			resourceType: "azurerm_linux_virtual_machine",
+			attributes: map[string]interface{}{
+				"size":                   "Standard_D4s_v5",
+				"source_image_reference": ubuntu("22_04-lts-gen2"),
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "Standard_D4ps_v5",
+			expectNotes:     "use sku 22_04-lts-arm64 of Canonical:0001-com-ubuntu-server-jammy",
+		},
+		{
+			name:         "Esv5 VM",
+			resourceType: "azurerm_linux_virtual_machine",
+			attributes: map[string]interface{}{
+				"size": "Standard_E4s_v5",
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "Standard_E4ps_v5",
+			expectNotes:     "Can migrate to Arm-based VM size Standard_E4ps_v5",
+		},
+		{
+			name:         "Low memory local disk VM from a custom image",
+			resourceType: "azurerm_linux_virtual_machine",
+			attributes: map[string]interface{}{
+				"size":            "Standard_D4lds_v5",
+				"source_image_id": "/subscriptions/0000/resourceGroups/images/providers/Microsoft.Compute/images/app",
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "Standard_D4plds_v5",
+			expectNotes:     "Requires an arm64 image in place of /subscriptions/0000",
+		},
+		{
+			name:         "AMD Dasv4 VM",
+			resourceType: "azurerm_linux_virtual_machine",
+			attributes: map[string]interface{}{
+				"size": "Standard_D96as_v4",
+			},
+			expectARM64: false,
+			expectArch:  "X86_64",
+			expectNotes: "No Arm-based VM size available for Standard_D96as_v4",
+		},
+		{
+			name:         "Dpsv5 VM with arm64 image",
+			resourceType: "azurerm_linux_virtual_machine",
+			attributes: map[string]interface{}{
+				"size":                   "Standard_D4ps_v5",
+				"source_image_reference": ubuntu("22_04-lts-arm64"),
+			},
+			expectARM64:     true,
+			expectAlready:   true,
+			expectArch:      "ARM64",
+			expectRecommend: "ARM64",
+			expectNotes:     "Already using Arm-based VM size",
+		},
+		{
+			name:         "Dpsv5 VM with x86_64 image",
+			resourceType: "azurerm_linux_virtual_machine",
+			attributes: map[string]interface{}{
+				"size":                   "Standard_D4ps_v5",
+				"source_image_reference": ubuntu("22_04-lts-gen2"),
+			},
+			expectARM64: false,
+			expectArch:  "ARM64",
+			expectNotes: "Misconfigured: Arm size Standard_D4ps_v5 boots x86_64 image",
+		},
+		{
+			name:         "Scale set",
+			resourceType: "azurerm_linux_virtual_machine_scale_set",
+			attributes: map[string]interface{}{
+				"sku":                    "Standard_E6ds_v5",
+				"source_image_reference": ubuntu("22_04-lts"),
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "Standard_E8pds_v5",
+			expectNotes:     "use sku 22_04-lts-arm64",
+		},
+		{
+			name:         "Scale set with x86_64 size and arm64 image",
+			resourceType: "azurerm_linux_virtual_machine_scale_set",
+			attributes: map[string]interface{}{
+				"sku":                    "Standard_D2s_v3",
+				"source_image_reference": ubuntu("22_04-lts-arm64"),
+			},
+			expectARM64: false,
+			expectArch:  "X86_64",
+			expectNotes: "Misconfigured: x86_64 size Standard_D2s_v3 boots arm64 image",
+		},
+		{
+			name:         "AKS node pool",
+			resourceType: "azurerm_kubernetes_cluster_node_pool",
+			attributes: map[string]interface{}{
+				"vm_size": "Standard_D8ds_v5",
+				"os_type": "Linux",
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "Standard_D8pds_v5",
+			expectNotes:     "AKS does not taint Arm nodes",
+		},
+		{
+			name:         "AKS Windows node pool",
+			resourceType: "azurerm_kubernetes_cluster_node_pool",
+			attributes: map[string]interface{}{
+				"vm_size": "Standard_D4s_v5",
+				"os_type": "Windows",
+			},
+			expectNA:    true,
+			expectArch:  "X86_64",
+			expectNotes: "Not applicable: Windows",
+		},
+		{
+			name:         "AKS Arm node pool",
+			resourceType: "azurerm_kubernetes_cluster_node_pool",
+			attributes: map[string]interface{}{
+				"vm_size": "Standard_D4pds_v5",
+			},
+			expectARM64:     true,
+			expectAlready:   true,
+			expectArch:      "ARM64",
+			expectRecommend: "ARM64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := parser.TerraformResource{
+				Mode:      "managed",
+				Type:      tt.resourceType,
+				Name:      "test",
+				Provider:  "provider[\"registry.terraform.io/hashicorp/azurerm\"]",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			}
+
+			analysis := AnalyzeResource(resource)
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("ARM64Compatible = %v, want %v (notes: %s)", analysis.ARM64Compatible, tt.expectARM64, analysis.Notes)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectAlready {
+				t.Errorf("AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectAlready)
+			}
+			if analysis.NotApplicable != tt.expectNA {
+				t.Errorf("NotApplicable = %v, want %v", analysis.NotApplicable, tt.expectNA)
+			}
+			if analysis.CurrentArch != tt.expectArch {
+				t.Errorf("CurrentArch = %v, want %v", analysis.CurrentArch, tt.expectArch)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
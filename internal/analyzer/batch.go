@@ -0,0 +1,258 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type BatchComputeEnvironmentAnalyzer struct{}
+
+func (a *BatchComputeEnvironmentAnalyzer) SupportedType() string {
+	return "aws_batch_compute_environment"
+}
+
+func (a *BatchComputeEnvironmentAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		computeResources := getFirstBlock(instance.Attributes["compute_resources"])
+		if computeResources == nil {
+			markUnknown(&analysis, "compute_resources", "No compute_resources found")
+			continue
+		}
+
+		computeType, _ := computeResources["type"].(string)
+		if strings.HasPrefix(computeType, "FARGATE") {
+			// The job definitions decide, and are counted, on their own
+			analysis.setArchitecture(ArchitectureAny)
+			analysis.decide(StatusNotApplicable, FindingNotApplicable, "compute_resources.0.type", computeType+" compute environment: architecture is set by each job definition's runtimePlatform.cpuArchitecture")
+			continue
+		}
+
+		a.analyzeInstanceTypes(&analysis, getStringList(computeResources["instance_type"]))
+	}
+	return analysis
+}
+
+// analyzeInstanceTypes evaluates an EC2 or SPOT compute environment. Its
+// instance_type list holds instance types, instance families or the
+// optimal and default_* keywords; a compute environment can only launch
+// instances of one architecture.
+func (a *BatchComputeEnvironmentAnalyzer) analyzeInstanceTypes(analysis *ARM64Analysis, instanceTypes []string) {
+	var armTypes, x86Types, recommended, missing []string
+	for _, instanceType := range instanceTypes {
+		alternative := ""
+		switch {
+		case isARM64BatchInstanceType(instanceType):
+			armTypes = append(armTypes, instanceType)
+			alternative = instanceType
+		default:
+			x86Types = append(x86Types, instanceType)
+			alternative = getARM64BatchAlternative(instanceType)
+			if alternative == "" {
+				missing = append(missing, instanceType)
+				continue
+			}
+		}
+		if !slices.Contains(recommended, alternative) {
+			recommended = append(recommended, alternative)
+		}
+	}
+
+	const attribute = "compute_resources.0.instance_type"
+	switch {
+	case len(instanceTypes) == 0:
+		markUnknown(analysis, attribute, "No instance types found in compute_resources")
+	case len(armTypes) > 0 && len(x86Types) > 0:
+		analysis.setArchitecture(ArchitectureMixed)
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, attribute, fmt.Sprintf("instance_type mixes arm64 (%s) and x86_64 (%s); a compute environment can only use one architecture",
+			strings.Join(armTypes, ", "), strings.Join(x86Types, ", ")))
+	case len(x86Types) == 0:
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, attribute, "Already using ARM64 instance types")
+	case len(missing) == len(x86Types):
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.decide(StatusBlocked, FindingNoARM64Option, attribute, "No ARM64 compatible instance type available for "+strings.Join(missing, ", "))
+	default:
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.RecommendedArch = strings.Join(recommended, ", ")
+		analysis.decide(StatusMigratable, FindingMigratable, attribute, "Can migrate instance_type to "+analysis.RecommendedArch)
+		analysis.addFinding(FindingNote, "", "Job definitions using this compute environment need arm64 images")
+		if len(missing) > 0 {
+			analysis.addFinding(FindingNoARM64Option, attribute, "No direct ARM64 alternative for "+strings.Join(missing, ", "))
+		}
+	}
+}
+
+type BatchJobDefinitionAnalyzer struct {
+	ctx *Context
+}
+
+func (a *BatchJobDefinitionAnalyzer) SupportedType() string {
+	return "aws_batch_job_definition"
+}
+
+func (a *BatchJobDefinitionAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		containers, err := parseBatchContainers(instance.Attributes)
+		if err != nil {
+			markUnknown(&analysis, "container_properties", "Could not parse job definition properties: "+err.Error())
+			continue
+		}
+
+		if slices.Contains(getStringList(instance.Attributes["platform_capabilities"]), "FARGATE") {
+			if !a.analyzeFargate(&analysis, containers) {
+				continue
+			}
+		} else {
+			// Jobs are submitted to queues at run time, so the compute
+			// environment they run on cannot be resolved from the state
+			analysis.setArchitecture(ArchitectureUnknown)
+			analysis.decide(StatusUnknown, FindingUnverified, "platform_capabilities", "EC2 job: runs on the architecture of the compute environment it is submitted to, which is analyzed separately")
+		}
+
+		var results, statuses, blocked []string
+		for _, container := range containers {
+			if container.Image == "" {
+				continue
+			}
+			status := a.ctx.checkContainerImage(container.Image)
+			results = append(results, container.Image+": "+status)
+			statuses = append(statuses, status)
+			if status == containerImageX86Only {
+				blocked = append(blocked, container.Image)
+			}
+		}
+
+		if len(blocked) > 0 {
+			if analysis.Status == StatusAlreadyARM64 {
+				analysis.decide(StatusMisconfigured, FindingMisconfigured, "container_properties", "Using ARM64 but images lack an arm64 variant: "+strings.Join(blocked, ", "))
+			} else {
+				analysis.RecommendedArch = ""
+				analysis.decide(StatusBlocked, FindingBlocked, "container_properties", "images without an arm64 variant: "+strings.Join(blocked, ", "))
+			}
+		}
+		if len(results) > 0 {
+			analysis.addFinding(getImageFindingCode(statuses), "container_properties", "Images: "+strings.Join(results, "; "))
+		}
+	}
+	return analysis
+}
+
+// analyzeFargate reads the runtimePlatform of a Fargate job, returning false
+// when the job is not applicable and needs no further checks.
+func (a *BatchJobDefinitionAnalyzer) analyzeFargate(analysis *ARM64Analysis, containers []batchContainer) bool {
+	for _, container := range containers {
+		if isWindowsOperatingSystem(container.RuntimePlatform.OperatingSystemFamily) {
+			markNotApplicableWindows(analysis, container.RuntimePlatform.OperatingSystemFamily)
+			return false
+		}
+	}
+
+	for _, container := range containers {
+		if container.RuntimePlatform.CPUArchitecture == "ARM64" {
+			analysis.setArchitecture(ArchitectureARM64)
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "container_properties", "Already using ARM64 architecture")
+			return true
+		}
+	}
+
+	analysis.setDefaultArchitecture(ArchitectureX86_64)
+	analysis.RecommendedArch = "ARM64"
+	analysis.decide(StatusMigratable, FindingMigratable, "container_properties", "Can set runtimePlatform { cpuArchitecture = \"ARM64\" } in container_properties")
+	return true
+}
+
+type batchContainer struct {
+	Image           string `json:"image"`
+	RuntimePlatform struct {
+		CPUArchitecture       string `json:"cpuArchitecture"`
+		OperatingSystemFamily string `json:"operatingSystemFamily"`
+	} `json:"runtimePlatform"`
+}
+
+// parseBatchContainers decodes the containers of a job definition from
+// container_properties or, for multi-node jobs, node_properties. Both are
+// stored as JSON-encoded strings.
+func parseBatchContainers(attributes map[string]any) ([]batchContainer, error) {
+	if properties, ok := attributes["container_properties"].(string); ok && properties != "" {
+		var container batchContainer
+		if err := json.Unmarshal([]byte(properties), &container); err != nil {
+			return nil, err
+		}
+		return []batchContainer{container}, nil
+	}
+
+	properties, ok := attributes["node_properties"].(string)
+	if !ok || properties == "" {
+		return nil, nil
+	}
+
+	var nodeProperties struct {
+		NodeRangeProperties []struct {
+			Container batchContainer `json:"container"`
+		} `json:"nodeRangeProperties"`
+	}
+	if err := json.Unmarshal([]byte(properties), &nodeProperties); err != nil {
+		return nil, err
+	}
+
+	var containers []batchContainer
+	for _, nodeRange := range nodeProperties.NodeRangeProperties {
+		containers = append(containers, nodeRange.Container)
+	}
+	return containers, nil
+}
+
+// isARM64BatchInstanceType accepts instance types, instance families and the
+// default_arm64 keyword.
+func isARM64BatchInstanceType(instanceType string) bool {
+	if instanceType == "default_arm64" {
+		return true
+	}
+	if !strings.Contains(instanceType, ".") {
+		instanceType += "."
+	}
+	return isARM64InstanceType(instanceType)
+}
+
+func getARM64BatchAlternative(instanceType string) string {
+	if strings.Contains(instanceType, ".") {
+		return getARM64Alternative(instanceType)
+	}
+	return getBatchInstanceFamilyX86ToArm64Map()[instanceType]
+}
+
+func getBatchInstanceFamilyX86ToArm64Map() map[string]string {
+	return map[string]string{
+		// optimal picks from the C4, M4 and R4 families (or newer x86 families)
+		"optimal":        "default_arm64",
+		"default_x86_64": "default_arm64",
+		"t3":             "t4g",
+		"m4":             "m7g",
+		"m5":             "m7g",
+		"m6i":            "m8g",
+		"c4":             "c7g",
+		"c5":             "c7g",
+		"c6i":            "c8g",
+		"r4":             "r7g",
+		"r5":             "r7g",
+		"r6i":            "r8g",
+	}
+}
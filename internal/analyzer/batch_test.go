@@ -0,0 +1,180 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestBatchComputeEnvironmentAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name            string
+		computeType     string
+		instanceTypes   []any
+		expectARM64     bool
+		expectUsing     bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name:            "optimal and x86 families",
+			computeType:     "EC2",
+			instanceTypes:   []any{"optimal", "c5", "m5.large"},
+			expectARM64:     true,
+			expectRecommend: "default_arm64, c7g, m7g.large",
+			expectNotes:     "Can migrate instance_type to default_arm64, c7g, m7g.large",
+		},
+		{
+			name:          "Graviton families",
+			computeType:   "SPOT",
+			instanceTypes: []any{"c7g", "m7g.xlarge"},
+			expectARM64:   true,
+			expectUsing:   true,
+			expectNotes:   "Already using ARM64 instance types",
+		},
+		{
+			name:          "mixed architectures are misconfigured",
+			computeType:   "EC2",
+			instanceTypes: []any{"default_arm64", "c5"},
+			expectARM64:   false,
+			expectNotes:   "Misconfigured: instance_type mixes arm64 (default_arm64) and x86_64 (c5)",
+		},
+		{
+			name:          "GPU family has no ARM64 alternative",
+			computeType:   "EC2",
+			instanceTypes: []any{"p3"},
+			expectARM64:   false,
+			expectNotes:   "No ARM64 compatible instance type available for p3",
+		},
+		{
+			name:        "Fargate compute environment",
+			computeType: "FARGATE_SPOT",
+			expectARM64: false,
+			expectNotes: "architecture is set by each job definition",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &BatchComputeEnvironmentAnalyzer{}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type: "aws_batch_compute_environment",
+				Name: "jobs",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"compute_resources": []any{
+								map[string]any{
+									"type":          tt.computeType,
+									"instance_type": tt.instanceTypes,
+								},
+							},
+						},
+					},
+				},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
+
+func TestBatchJobDefinitionAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name         string
+		attributes   map[string]interface{}
+		expectARM64  bool
+		expectUsing  bool
+		expectNotApp bool
+		expectStatus Status
+		expectNotes  string
+	}{
+		{
+			name: "Fargate job on ARM64",
+			attributes: map[string]interface{}{
+				"platform_capabilities": []any{"FARGATE"},
+				"container_properties":  `{"image":"busybox","runtimePlatform":{"cpuArchitecture":"ARM64"}}`,
+			},
+			expectARM64: true,
+			expectUsing: true,
+			expectNotes: "Already using ARM64 architecture",
+		},
+		{
+			name: "Fargate job defaulting to x86",
+			attributes: map[string]interface{}{
+				"platform_capabilities": []any{"FARGATE"},
+				"container_properties":  `{"image":"busybox"}`,
+			},
+			expectARM64: true,
+			expectNotes: `Can set runtimePlatform { cpuArchitecture = "ARM64" }`,
+		},
+		{
+			name: "Windows Fargate job",
+			attributes: map[string]interface{}{
+				"platform_capabilities": []any{"FARGATE"},
+				"container_properties":  `{"image":"mcr.microsoft.com/windows/servercore","runtimePlatform":{"operatingSystemFamily":"WINDOWS_SERVER_2019_CORE"}}`,
+			},
+			expectNotApp: true,
+			expectNotes:  "Not applicable",
+		},
+		{
+			name: "EC2 multi-node job",
+			attributes: map[string]interface{}{
+				"type":            "multinode",
+				"node_properties": `{"nodeRangeProperties":[{"container":{"image":"mpi-worker"}}]}`,
+			},
+			expectARM64:  false,
+			expectStatus: StatusUnknown,
+			expectNotes:  "Images: mpi-worker: not verified",
+		},
+		{
+			name: "EC2 job defers to its compute environment",
+			attributes: map[string]interface{}{
+				"platform_capabilities": []any{"EC2"},
+				"container_properties":  `{"image":"busybox"}`,
+			},
+			expectARM64:  false,
+			expectStatus: StatusUnknown,
+			expectNotes:  "EC2 job: runs on the architecture of the compute environment it is submitted to",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &BatchJobDefinitionAnalyzer{}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type:      "aws_batch_job_definition",
+				Name:      "job",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if analysis.NotApplicable != tt.expectNotApp {
+				t.Errorf("Analyze() NotApplicable = %v, want %v", analysis.NotApplicable, tt.expectNotApp)
+			}
+			if tt.expectStatus != "" && analysis.Status != tt.expectStatus {
+				t.Errorf("Analyze() Status = %v, want %v", analysis.Status, tt.expectStatus)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
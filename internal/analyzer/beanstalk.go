@@ -0,0 +1,152 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type ElasticBeanstalkAnalyzer struct{}
+
+func (a *ElasticBeanstalkAnalyzer) SupportedType() string {
+	return "aws_elastic_beanstalk_environment"
+}
+
+func (a *ElasticBeanstalkAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		solutionStack, _ := instance.Attributes["solution_stack_name"].(string)
+		if solutionStack == "" {
+			// Custom and pinned platforms are referenced by ARN, whose path
+			// names the platform branch
+			solutionStack, _ = instance.Attributes["platform_arn"].(string)
+		}
+		if strings.Contains(solutionStack, "Windows") {
+			markNotApplicableWindows(&analysis, solutionStack)
+			continue
+		}
+
+		settings := getBeanstalkSettings(instance.Attributes)
+		setting := beanstalkSetting{Namespace: "aws:ec2:instances", Name: "InstanceTypes"}
+		value := settings[setting]
+		if value == "" {
+			setting = beanstalkSetting{Namespace: "aws:autoscaling:launchconfiguration", Name: "InstanceType"}
+			value = settings[setting]
+		}
+		if value == "" {
+			markUnknown(&analysis, "setting", "No instance types found in settings")
+			continue
+		}
+
+		a.analyzeInstanceTypes(&analysis, setting, value, settings[beanstalkSetting{Namespace: "aws:ec2:instances", Name: "SupportedArchitectures"}])
+
+		if analysis.canMigrate() && !isARM64BeanstalkPlatform(solutionStack) {
+			analysis.RecommendedArch = ""
+			analysis.decide(StatusBlocked, FindingBlocked, "solution_stack_name", "Platform "+solutionStack+" does not support arm64; migrate to an Amazon Linux 2 or Amazon Linux 2023 platform branch first")
+		}
+	}
+	return analysis
+}
+
+// analyzeInstanceTypes evaluates the comma-separated instance types of a
+// setting and recommends Graviton types in the same setting.
+func (a *ElasticBeanstalkAnalyzer) analyzeInstanceTypes(analysis *ARM64Analysis, setting beanstalkSetting, value, supportedArchitectures string) {
+	var armTypes, x86Types, recommended, missing []string
+	for _, instanceType := range strings.Split(value, ",") {
+		instanceType = strings.TrimSpace(instanceType)
+		switch {
+		case isARM64InstanceType(instanceType):
+			armTypes = append(armTypes, instanceType)
+			recommended = append(recommended, instanceType)
+		case hasARM64Alternative(instanceType):
+			x86Types = append(x86Types, instanceType)
+			recommended = append(recommended, getARM64Alternative(instanceType))
+		default:
+			x86Types = append(x86Types, instanceType)
+			missing = append(missing, instanceType)
+		}
+	}
+
+	switch {
+	case len(armTypes) > 0 && len(x86Types) > 0:
+		analysis.setArchitecture(ArchitectureMixed)
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, "setting", fmt.Sprintf("%s %s mixes arm64 (%s) and x86_64 (%s)",
+			setting.Namespace, setting.Name, strings.Join(armTypes, ", "), strings.Join(x86Types, ", ")))
+	case len(x86Types) == 0:
+		analysis.setArchitecture(ArchitectureARM64)
+		if supportedArchitectures != "" && supportedArchitectures != "arm64" {
+			analysis.RecommendedArch = ""
+			analysis.decide(StatusMisconfigured, FindingMisconfigured, "setting", "aws:ec2:instances SupportedArchitectures is "+supportedArchitectures+" but instance types are arm64")
+			return
+		}
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "setting", "Already using ARM64 instance types")
+	case len(missing) > 0:
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.decide(StatusBlocked, FindingNoARM64Option, "setting", "No ARM64 compatible instance type available for "+strings.Join(missing, ", "))
+	default:
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.RecommendedArch = strings.Join(recommended, ",")
+		message := fmt.Sprintf("Set %s %s = %q", setting.Namespace, setting.Name, analysis.RecommendedArch)
+		if setting.Name == "InstanceTypes" {
+			message += ` and SupportedArchitectures = "arm64"`
+		}
+		analysis.decide(StatusMigratable, FindingMigratable, "setting", message)
+		analysis.addFinding(FindingUnverified, "", "Application dependencies must support arm64")
+	}
+}
+
+type beanstalkSetting struct {
+	Namespace string
+	Name      string
+}
+
+// getBeanstalkSettings indexes the environment's setting blocks, falling back
+// to the computed all_settings for options left at their defaults.
+func getBeanstalkSettings(attributes map[string]any) map[beanstalkSetting]string {
+	settings := make(map[beanstalkSetting]string)
+	for _, attribute := range []string{"all_settings", "setting"} {
+		blocks, _ := attributes[attribute].([]any)
+		for _, block := range blocks {
+			blockMap, ok := block.(map[string]any)
+			if !ok {
+				continue
+			}
+			namespace, _ := blockMap["namespace"].(string)
+			name, _ := blockMap["name"].(string)
+			value, _ := blockMap["value"].(string)
+			if value != "" {
+				settings[beanstalkSetting{Namespace: namespace, Name: name}] = value
+			}
+		}
+	}
+	return settings
+}
+
+// isARM64BeanstalkPlatform reports whether a solution stack belongs to a
+// platform branch that offers arm64 instances. Only Amazon Linux 2 and Amazon
+// Linux 2023 based platforms do.
+func isARM64BeanstalkPlatform(solutionStack string) bool {
+	if solutionStack == "" {
+		return true
+	}
+	_, branch, found := strings.Cut(solutionStack, "Amazon Linux ")
+	if !found {
+		return false
+	}
+	// Platform ARNs separate the branch from the version with a slash, e.g.
+	// "64bit Amazon Linux 2023/4.3.0"; the legacy branch is "Amazon Linux 2018.03"
+	for _, arm64Branch := range []string{"2 ", "2/", "2023 ", "2023/"} {
+		if strings.HasPrefix(branch, arm64Branch) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,118 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestElasticBeanstalkAnalyzer_Analyze(t *testing.T) {
+	setting := func(namespace, name, value string) map[string]any {
+		return map[string]any{"namespace": namespace, "name": name, "value": value}
+	}
+
+	tests := []struct {
+		name            string
+		solutionStack   string
+		settings        []any
+		expectARM64     bool
+		expectUsing     bool
+		expectNotApp    bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name:          "InstanceTypes on Amazon Linux 2023",
+			solutionStack: "64bit Amazon Linux 2023 v4.3.0 running Python 3.11",
+			settings: []any{
+				setting("aws:ec2:instances", "InstanceTypes", "t3.micro,t3.small"),
+			},
+			expectARM64:     true,
+			expectRecommend: "t4g.micro,t4g.small",
+			expectNotes:     `Set aws:ec2:instances InstanceTypes = "t4g.micro,t4g.small" and SupportedArchitectures = "arm64"`,
+		},
+		{
+			name:          "legacy launch configuration InstanceType",
+			solutionStack: "64bit Amazon Linux 2 v3.6.0 running Docker",
+			settings: []any{
+				setting("aws:autoscaling:launchconfiguration", "InstanceType", "m5.large"),
+			},
+			expectARM64:     true,
+			expectRecommend: "m7g.large",
+			expectNotes:     `Set aws:autoscaling:launchconfiguration InstanceType = "m7g.large"`,
+		},
+		{
+			name:          "arm64 environment",
+			solutionStack: "64bit Amazon Linux 2023 v6.1.0 running Node.js 20",
+			settings: []any{
+				setting("aws:ec2:instances", "InstanceTypes", "t4g.small"),
+				setting("aws:ec2:instances", "SupportedArchitectures", "arm64"),
+			},
+			expectARM64: true,
+			expectUsing: true,
+			expectNotes: "Already using ARM64 instance types",
+		},
+		{
+			name:          "mixed InstanceTypes are misconfigured",
+			solutionStack: "64bit Amazon Linux 2023 v4.3.0 running Python 3.11",
+			settings: []any{
+				setting("aws:ec2:instances", "InstanceTypes", "t4g.small,t3.small"),
+			},
+			expectARM64: false,
+			expectNotes: "Misconfigured: aws:ec2:instances InstanceTypes mixes arm64 (t4g.small) and x86_64 (t3.small)",
+		},
+		{
+			name:          "Amazon Linux AMI platform has no arm64",
+			solutionStack: "64bit Amazon Linux 2018.03 v2.9.0 running Python 3.6",
+			settings: []any{
+				setting("aws:autoscaling:launchconfiguration", "InstanceType", "t3.small"),
+			},
+			expectARM64: false,
+			expectNotes: "does not support arm64",
+		},
+		{
+			name:          "Windows platform",
+			solutionStack: "64bit Windows Server 2019 v2.11.0 running IIS 10.0",
+			settings: []any{
+				setting("aws:autoscaling:launchconfiguration", "InstanceType", "t3.small"),
+			},
+			expectNotApp: true,
+			expectNotes:  "Not applicable",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &ElasticBeanstalkAnalyzer{}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type: "aws_elastic_beanstalk_environment",
+				Name: "app",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"solution_stack_name": tt.solutionStack,
+							"setting":             tt.settings,
+						},
+					},
+				},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if analysis.NotApplicable != tt.expectNotApp {
+				t.Errorf("Analyze() NotApplicable = %v, want %v", analysis.NotApplicable, tt.expectNotApp)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
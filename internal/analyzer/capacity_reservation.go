@@ -0,0 +1,249 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type CapacityReservationAnalyzer struct {
+	ctx *Context
+}
+
+func (a *CapacityReservationAnalyzer) SupportedType() string {
+	return "aws_ec2_capacity_reservation"
+}
+
+func (a *CapacityReservationAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		if platform, ok := instance.Attributes["instance_platform"].(string); ok && isWindowsOperatingSystem(platform) {
+			markNotApplicableWindows(&analysis, platform)
+			continue
+		}
+
+		instanceType, ok := instance.Attributes["instance_type"].(string)
+		if !ok || instanceType == "" {
+			continue
+		}
+		reservation := capacityReservation{InstanceType: instanceType}
+		reservation.ID, _ = instance.Attributes["id"].(string)
+		reservation.AvailabilityZone, _ = instance.Attributes["availability_zone"].(string)
+		reservation.MatchCriteria, _ = instance.Attributes["instance_match_criteria"].(string)
+		reservation.Tenancy, _ = instance.Attributes["tenancy"].(string)
+		applyReservedCapacity(&analysis, "instance_type", instanceType, getARM64Alternative(instanceType),
+			a.ctx.findCapacityReservationUsers(reservation))
+	}
+	return analysis
+}
+
+// capacityReservation holds the attributes of an aws_ec2_capacity_reservation
+// that decide which instances launch into it.
+type capacityReservation struct {
+	ID               string
+	InstanceType     string
+	AvailabilityZone string
+	// MatchCriteria is "open" or "targeted"; the provider defaults to "open"
+	MatchCriteria string
+	Tenancy       string
+}
+
+// findCapacityReservationUsers returns the addresses of resources in the
+// state that launch a migrating instance type into the reservation. Instances
+// and launch templates can target a reservation through
+// capacity_reservation_specification; untargeted instances only use an open
+// reservation when their instance type, availability zone and tenancy match.
+// Reservations are only analyzed for Linux platforms, so Windows users are
+// skipped.
+func (c *Context) findCapacityReservationUsers(reservation capacityReservation) []string {
+	return c.findMigratingInstanceTypeUsers(
+		func(instanceType string) bool { return instanceType == reservation.InstanceType },
+		reservation.accepts)
+}
+
+// accepts reports whether instances launched by a resource of the given type
+// use the reservation. A resource that does not pin an availability zone, such
+// as a launch template without a placement block, may launch in any of them.
+func (r capacityReservation) accepts(resourceType string, attributes map[string]any) bool {
+	if platform, _ := attributes["platform"].(string); isWindowsOperatingSystem(platform) {
+		return false
+	}
+
+	specification := getFirstBlock(attributes["capacity_reservation_specification"])
+	if target := getFirstBlock(specification["capacity_reservation_target"]); target != nil {
+		// Targeted instances only launch into the reservation or resource
+		// group they name; groups cannot be resolved from the state
+		id, _ := target["capacity_reservation_id"].(string)
+		return id != "" && id == r.ID
+	}
+	if preference, _ := specification["capacity_reservation_preference"].(string); preference == "none" {
+		return false
+	}
+	if r.MatchCriteria != "" && r.MatchCriteria != "open" {
+		return false
+	}
+
+	var availabilityZones []string
+	var tenancy string
+	switch resourceType {
+	case "aws_instance":
+		if availabilityZone, _ := attributes["availability_zone"].(string); availabilityZone != "" {
+			availabilityZones = []string{availabilityZone}
+		}
+		tenancy, _ = attributes["tenancy"].(string)
+	case "aws_launch_template":
+		placement := getFirstBlock(attributes["placement"])
+		if availabilityZone, _ := placement["availability_zone"].(string); availabilityZone != "" {
+			availabilityZones = []string{availabilityZone}
+		}
+		tenancy, _ = placement["tenancy"].(string)
+	case "aws_launch_configuration":
+		tenancy, _ = attributes["placement_tenancy"].(string)
+	case "aws_autoscaling_group":
+		availabilityZones = getStringList(attributes["availability_zones"])
+	}
+	if len(availabilityZones) > 0 && !slices.Contains(availabilityZones, r.AvailabilityZone) {
+		return false
+	}
+	return getTenancy(tenancy) == getTenancy(r.Tenancy)
+}
+
+// getTenancy returns the tenancy of an instance or reservation, which
+// defaults to shared hardware.
+func getTenancy(tenancy string) string {
+	if tenancy == "" {
+		return "default"
+	}
+	return tenancy
+}
+
+type DedicatedHostAnalyzer struct {
+	ctx *Context
+}
+
+func (a *DedicatedHostAnalyzer) SupportedType() string {
+	return "aws_ec2_host"
+}
+
+func (a *DedicatedHostAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		// Hosts support either a single instance type or a whole family
+		if instanceType, ok := instance.Attributes["instance_type"].(string); ok && instanceType != "" {
+			applyReservedCapacity(&analysis, "instance_type", instanceType, getARM64Alternative(instanceType),
+				a.ctx.findMigratingInstanceTypeUsers(func(candidate string) bool { return candidate == instanceType }, nil))
+			continue
+		}
+
+		family, ok := instance.Attributes["instance_family"].(string)
+		if !ok || family == "" {
+			continue
+		}
+		applyReservedCapacity(&analysis, "instance_family", family, getARM64InstanceFamilyAlternative(family),
+			a.ctx.findMigratingInstanceTypeUsers(func(candidate string) bool { return strings.HasPrefix(candidate, family+".") }, nil))
+	}
+	return analysis
+}
+
+// applyReservedCapacity records the decision for capacity reserved for an
+// instance type or family. Reserved capacity is not migrated by itself; it
+// becomes stranded when the instances using it follow our recommendations.
+func applyReservedCapacity(analysis *ARM64Analysis, attribute, reserved, alternative string, users []string) {
+	instanceType := reserved
+	if !strings.Contains(instanceType, ".") {
+		// Families match the instance type prefixes, e.g. "m7g."
+		instanceType += "."
+	}
+	analysis.setArchitecture(getArchFromInstanceType(instanceType))
+
+	switch {
+	case analysis.Architecture == ArchitectureARM64:
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, attribute, "Already reserving ARM64 capacity")
+	case alternative == "":
+		analysis.decide(StatusBlocked, FindingNoARM64Option, attribute, "No ARM64 alternative for "+reserved)
+	case len(users) > 0:
+		analysis.RecommendedArch = alternative
+		analysis.decide(StatusMigratable, FindingOrphanedCapacity, attribute, strings.Join(users, ", ")+" can migrate off "+reserved)
+		analysis.addFinding(FindingNote, attribute, "Reserve "+alternative+" instead")
+	default:
+		analysis.RecommendedArch = alternative
+		analysis.decide(StatusMigratable, FindingMigratable, attribute, "Can reserve "+alternative+" instead")
+		analysis.addFinding(FindingNote, "", "No resources using "+reserved+" found in state")
+	}
+}
+
+// findMigratingInstanceTypeUsers returns the addresses of resources in the
+// state that launch a matching x86_64 instance type with a recommended ARM64
+// alternative, such as the users of a dedicated host. accepts, when not nil,
+// further filters the resources, e.g. on where they launch.
+func (c *Context) findMigratingInstanceTypeUsers(match func(instanceType string) bool, accepts func(resourceType string, attributes map[string]any) bool) []string {
+	var users []string
+	for _, resourceType := range []string{
+		"aws_instance",
+		"aws_launch_template",
+		"aws_launch_configuration",
+		"aws_autoscaling_group",
+		"aws_ec2_fleet",
+		"aws_spot_fleet_request",
+	} {
+		for _, resource := range c.FindResources(resourceType) {
+			for _, instance := range resource.Instances {
+				if hasMigratingInstanceType(instance.Attributes, match) && (accepts == nil || accepts(resourceType, instance.Attributes)) {
+					users = append(users, resource.GetFullAddress())
+					break
+				}
+			}
+		}
+	}
+	return users
+}
+
+// hasMigratingInstanceType walks nested blocks, such as fleet and mixed
+// instances policy overrides, for a matching instance_type.
+func hasMigratingInstanceType(value any, match func(instanceType string) bool) bool {
+	switch node := value.(type) {
+	case map[string]any:
+		for key, child := range node {
+			if instanceType, ok := child.(string); ok && key == "instance_type" {
+				if match(instanceType) && hasARM64Alternative(instanceType) {
+					return true
+				}
+				continue
+			}
+			if hasMigratingInstanceType(child, match) {
+				return true
+			}
+		}
+	case []any:
+		for _, child := range node {
+			if hasMigratingInstanceType(child, match) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getARM64InstanceFamilyAlternative returns the Graviton family recommended
+// for the instance types of an x86_64 family, e.g. "m5" -> "m7g".
+func getARM64InstanceFamilyAlternative(family string) string {
+	for instanceType, alternative := range getX86ToArm64Map() {
+		if strings.HasPrefix(instanceType, family+".") {
+			armFamily, _, _ := strings.Cut(alternative, ".")
+			return armFamily
+		}
+	}
+	return ""
+}
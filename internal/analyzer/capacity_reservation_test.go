@@ -0,0 +1,292 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestReservedCapacityAnalyzers_Analyze(t *testing.T) {
+	resources := []parser.TerraformResource{
+		{
+			Mode: "managed",
+			Type: "aws_instance",
+			Name: "web",
+			Instances: []parser.ResourceInstance{
+				{Attributes: map[string]interface{}{"instance_type": "m5.large", "availability_zone": "us-east-1a"}},
+			},
+		},
+		{
+			Mode: "managed",
+			Type: "aws_instance",
+			Name: "pinned",
+			Instances: []parser.ResourceInstance{
+				{
+					Attributes: map[string]interface{}{
+						"instance_type":     "c5.2xlarge",
+						"availability_zone": "us-east-1b",
+						"capacity_reservation_specification": []any{
+							map[string]any{
+								"capacity_reservation_preference": "",
+								"capacity_reservation_target": []any{
+									map[string]any{"capacity_reservation_id": "cr-0123"},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Mode: "managed",
+			Type: "aws_instance",
+			Name: "opted_out",
+			Instances: []parser.ResourceInstance{
+				{
+					Attributes: map[string]interface{}{
+						"instance_type":     "c5.large",
+						"availability_zone": "us-east-1a",
+						"capacity_reservation_specification": []any{
+							map[string]any{"capacity_reservation_preference": "none"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Mode: "managed",
+			Type: "aws_launch_template",
+			Name: "dedicated",
+			Instances: []parser.ResourceInstance{
+				{
+					Attributes: map[string]interface{}{
+						"instance_type": "r5.large",
+						"placement": []any{
+							map[string]any{"availability_zone": "us-east-1a", "tenancy": "dedicated"},
+						},
+					},
+				},
+			},
+		},
+		{
+			Mode: "managed",
+			Type: "aws_launch_template",
+			Name: "any_zone",
+			Instances: []parser.ResourceInstance{
+				{Attributes: map[string]interface{}{"instance_type": "m5.2xlarge"}},
+			},
+		},
+		{
+			Mode: "managed",
+			Type: "aws_launch_configuration",
+			Name: "legacy",
+			Instances: []parser.ResourceInstance{
+				{Attributes: map[string]interface{}{"instance_type": "c5.xlarge"}},
+			},
+		},
+		{
+			Mode: "managed",
+			Type: "aws_autoscaling_group",
+			Name: "zonal",
+			Instances: []parser.ResourceInstance{
+				{
+					Attributes: map[string]interface{}{
+						"availability_zones": []any{"us-east-1a"},
+						"mixed_instances_policy": []any{
+							map[string]any{
+								"launch_template": []any{
+									map[string]any{
+										"override": []any{
+											map[string]any{"instance_type": "m5.4xlarge"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Mode: "managed",
+			Type: "aws_autoscaling_group",
+			Name: "workers",
+			Instances: []parser.ResourceInstance{
+				{
+					Attributes: map[string]interface{}{
+						"mixed_instances_policy": []any{
+							map[string]any{
+								"launch_template": []any{
+									map[string]any{
+										"override": []any{
+											map[string]any{"instance_type": "m5.xlarge"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		resourceType    string
+		attributes      map[string]interface{}
+		expectARM64     bool
+		expectUsing     bool
+		expectNotApp    bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name:            "reservation orphaned by a migrating instance",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "m5.large", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1a", "instance_match_criteria": "open"},
+			expectARM64:     true,
+			expectRecommend: "m7g.large",
+			expectNotes:     "Would be orphaned: aws_instance.web can migrate off m5.large | Reserve m7g.large instead",
+		},
+		{
+			name:            "open reservation in another availability zone",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "m5.large", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1b", "instance_match_criteria": "open"},
+			expectARM64:     true,
+			expectRecommend: "m7g.large",
+			expectNotes:     "No resources using m5.large found in state",
+		},
+		{
+			name:            "targeted reservation ignores untargeted instances",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "m5.large", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1a", "instance_match_criteria": "targeted"},
+			expectARM64:     true,
+			expectRecommend: "m7g.large",
+			expectNotes:     "No resources using m5.large found in state",
+		},
+		{
+			name:            "targeted reservation orphaned by the instance targeting it",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"id": "cr-0123", "instance_type": "c5.2xlarge", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1b", "instance_match_criteria": "targeted"},
+			expectARM64:     true,
+			expectRecommend: "c7g.2xlarge",
+			expectNotes:     "Would be orphaned: aws_instance.pinned can migrate off c5.2xlarge",
+		},
+		{
+			name:            "reservation skipped by an instance that opted out",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "c5.large", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1a", "instance_match_criteria": "open"},
+			expectARM64:     true,
+			expectRecommend: "c7g.large",
+			expectNotes:     "No resources using c5.large found in state",
+		},
+		{
+			name:            "shared reservation ignores dedicated launch templates",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "r5.large", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1a", "instance_match_criteria": "open", "tenancy": "default"},
+			expectARM64:     true,
+			expectRecommend: "r7g.large",
+			expectNotes:     "No resources using r5.large found in state",
+		},
+		{
+			name:            "dedicated reservation orphaned by a dedicated launch template",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "r5.large", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1a", "instance_match_criteria": "open", "tenancy": "dedicated"},
+			expectARM64:     true,
+			expectRecommend: "r7g.large",
+			expectNotes:     "Would be orphaned: aws_launch_template.dedicated can migrate off r5.large",
+		},
+		{
+			name:            "launch template without an availability zone uses a reservation in any zone",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "m5.2xlarge", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1c", "instance_match_criteria": "open"},
+			expectARM64:     true,
+			expectRecommend: "m7g.2xlarge",
+			expectNotes:     "Would be orphaned: aws_launch_template.any_zone can migrate off m5.2xlarge",
+		},
+		{
+			name:            "reservation orphaned by a launch configuration",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "c5.xlarge", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1a", "instance_match_criteria": "open"},
+			expectARM64:     true,
+			expectRecommend: "c7g.xlarge",
+			expectNotes:     "Would be orphaned: aws_launch_configuration.legacy can migrate off c5.xlarge",
+		},
+		{
+			name:            "reservation orphaned by Auto Scaling group overrides",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "m5.xlarge", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1b", "instance_match_criteria": "open"},
+			expectARM64:     true,
+			expectRecommend: "m7g.xlarge",
+			expectNotes:     "Would be orphaned: aws_autoscaling_group.workers can migrate off m5.xlarge",
+		},
+		{
+			name:            "Auto Scaling group in other availability zones",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "m5.4xlarge", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1b", "instance_match_criteria": "open"},
+			expectARM64:     true,
+			expectRecommend: "m7g.4xlarge",
+			expectNotes:     "No resources using m5.4xlarge found in state",
+		},
+		{
+			name:            "targeted reservation ignores untargeted Auto Scaling groups",
+			resourceType:    "aws_ec2_capacity_reservation",
+			attributes:      map[string]interface{}{"instance_type": "m5.xlarge", "instance_platform": "Linux/UNIX", "availability_zone": "us-east-1b", "instance_match_criteria": "targeted"},
+			expectARM64:     true,
+			expectRecommend: "m7g.xlarge",
+			expectNotes:     "No resources using m5.xlarge found in state",
+		},
+		{
+			name:         "Windows reservation",
+			resourceType: "aws_ec2_capacity_reservation",
+			attributes:   map[string]interface{}{"instance_type": "m5.large", "instance_platform": "Windows"},
+			expectNotApp: true,
+			expectNotes:  "Not applicable",
+		},
+		{
+			name:            "host family orphaned by migrating overrides",
+			resourceType:    "aws_ec2_host",
+			attributes:      map[string]interface{}{"instance_family": "m5"},
+			expectARM64:     true,
+			expectRecommend: "m7g",
+			expectNotes:     "Would be orphaned: aws_instance.web, aws_launch_template.any_zone, aws_autoscaling_group.zonal, aws_autoscaling_group.workers can migrate off m5",
+		},
+		{
+			name:         "Graviton host",
+			resourceType: "aws_ec2_host",
+			attributes:   map[string]interface{}{"instance_type": "c7g.large"},
+			expectARM64:  true,
+			expectUsing:  true,
+			expectNotes:  "Already reserving ARM64 capacity",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &parser.TerraformState{Version: 4, Resources: resources}
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Type:      tt.resourceType,
+				Name:      "reserved",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			}, NewContext(state))
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if analysis.NotApplicable != tt.expectNotApp {
+				t.Errorf("Analyze() NotApplicable = %v, want %v", analysis.NotApplicable, tt.expectNotApp)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
@@ -2,11 +2,14 @@ package analyzer
 
 import (
 	"slices"
+	"strings"
 
 	"github.com/suer/tf-arm/internal/parser"
 )
 
-type CodeBuildAnalyzer struct{}
+type CodeBuildAnalyzer struct {
+	ctx *Context
+}
 
 func (a *CodeBuildAnalyzer) SupportedType() string {
 	return "aws_codebuild_project"
@@ -16,71 +19,272 @@ func (a *CodeBuildAnalyzer) Analyze(resource parser.TerraformResource) ARM64Anal
 	analysis := ARM64Analysis{
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
-		ARM64Compatible: true,
+		ARM64Compatible: false,
 	}
 
 	for _, instance := range resource.Instances {
-		if environment, exists := instance.Attributes["environment"]; exists {
-			envList, ok := environment.([]any)
-			if !ok {
-				continue
+		envList, ok := instance.Attributes["environment"].([]any)
+		if !ok || len(envList) == 0 {
+			continue
+		}
+		env, ok := envList[0].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		environmentType, _ := env["type"].(string)
+		image, _ := env["image"].(string)
+		computeType, _ := env["compute_type"].(string)
+		a.analyzeEnvironment(&analysis, environmentType, image, computeType)
+
+		if fleetARN := getCodeBuildFleetARN(env); fleetARN != "" {
+			analysis.addFinding(FindingNote, "environment.0.fleet", a.describeFleet(fleetARN))
+		}
+	}
+	return analysis
+}
+
+func (a *CodeBuildAnalyzer) analyzeEnvironment(analysis *ARM64Analysis, environmentType, image, computeType string) {
+	if strings.HasPrefix(environmentType, "WINDOWS") {
+		markNotApplicableWindows(analysis, environmentType)
+		return
+	}
+
+	typeArch := getCodeBuildEnvironmentTypeArch(environmentType)
+	if typeArch == ArchitectureUnknown && isARM64ComputeType(computeType) {
+		typeArch = ArchitectureARM64
+	}
+	imageArch := a.getImageArch(image)
+
+	if mismatch := getCodeBuildComputeTypeMismatch(environmentType, computeType); mismatch != "" {
+		analysis.setArchitecture(typeArch)
+		analysis.RecommendedArch = ""
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, "environment.0.compute_type", mismatch)
+		return
+	}
+
+	if typeArch == ArchitectureARM64 {
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.RecommendedArch = "ARM64"
+		if imageArch == ArchitectureX86_64 {
+			analysis.decide(StatusMisconfigured, FindingMisconfigured, "environment.0.image", environmentType+" with x86_64 image "+image)
+			return
+		}
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "environment.0.type", "Already using ARM64 environment type "+environmentType)
+		if imageArch == ArchitectureUnknown {
+			analysis.addFinding(FindingUnverified, "environment.0.image", "Verify image "+image+" is built for arm64")
+		}
+		return
+	}
+
+	if environmentType == "" {
+		analysis.setArchitecture(ArchitectureUnknown)
+		markUnknown(analysis, "environment.0.type", "environment type not in state")
+		return
+	}
+
+	analysis.setArchitecture(ArchitectureX86_64)
+	if imageArch == ArchitectureARM64 {
+		analysis.RecommendedArch = ""
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, "environment.0.image", environmentType+" with arm64 image "+image)
+		return
+	}
+
+	armType, hasARMType := getCodeBuildEnvironmentTypeX86ToArm64Map()[environmentType]
+	if !hasARMType {
+		analysis.RecommendedArch = ""
+		analysis.decide(StatusBlocked, FindingNoARM64Option, "environment.0.type", "No ARM64 environment type available for "+environmentType)
+		return
+	}
+
+	analysis.RecommendedArch = armType
+	armImage, changesOS := getCodeBuildARM64Image(image, armType)
+	if armImage == "" {
+		analysis.decide(StatusMigratable, FindingMigratable, "environment.0.type", "Can migrate to environment type "+armType)
+		analysis.addFinding(FindingUnverified, "environment.0.image", "Custom image "+image+" needs an arm64 variant")
+		return
+	}
+	analysis.decide(StatusMigratable, FindingMigratable, "environment.0.type", "Can migrate to environment type "+armType+" with image "+armImage)
+	if changesOS {
+		analysis.addFinding(FindingPrerequisite, "environment.0.image", "No aarch64 curated image for "+image+"; the build moves from Ubuntu to Amazon Linux 2023, so check the buildspec's commands and packages")
+	}
+}
+
+// getImageArch infers the architecture of a build image from the CodeBuild
+// curated image naming scheme or the image cache, or returns
+// ArchitectureUnknown when it cannot be determined.
+func (a *CodeBuildAnalyzer) getImageArch(image string) Architecture {
+	switch {
+	case image == "":
+		return ArchitectureUnknown
+	case strings.Contains(image, "aarch64"):
+		return ArchitectureARM64
+	case strings.Contains(image, "x86_64"), strings.HasPrefix(image, "aws/codebuild/standard:"):
+		return ArchitectureX86_64
+	}
+
+	if architectures, found := a.ctx.ImageArchitectures(image); found {
+		if slices.Contains(architectures, "arm64") {
+			// Multi-arch images run on either environment type
+			if slices.Contains(architectures, "amd64") {
+				return ArchitectureUnknown
 			}
-			if len(envList) > 0 {
-				env, ok := envList[0].(map[string]any)
-				if !ok {
-					continue
-				}
-				if computeType, exists := env["compute_type"]; exists {
-					computeTypeStr, ok := computeType.(string)
-					if !ok {
-						continue
-					}
-					if isARM64ComputeType(computeTypeStr) {
-						analysis.CurrentArch = "ARM64"
-						analysis.AlreadyUsingARM64 = true
-						analysis.RecommendedArch = "ARM64"
-						analysis.Notes = "Already using ARM64 compute type"
-					} else if hasARM64ComputeTypeAlternative(computeTypeStr) {
-						analysis.CurrentArch = "X86_64"
-						analysis.RecommendedArch = getARM64ComputeTypeAlternative(computeTypeStr)
-						analysis.Notes = "Can migrate to ARM64 compute type: " + analysis.RecommendedArch
-					} else {
-						analysis.CurrentArch = "X86_64"
-						analysis.ARM64Compatible = false
-						analysis.Notes = "No ARM64 compatible compute type available"
-					}
-				}
+			return ArchitectureARM64
+		}
+		return ArchitectureX86_64
+	}
+	return ArchitectureUnknown
+}
+
+// describeFleet reports the reserved capacity fleet a project builds on, since
+// its environment type must change together with the project's.
+func (a *CodeBuildAnalyzer) describeFleet(fleetARN string) string {
+	for _, fleet := range a.ctx.FindResources("aws_codebuild_fleet") {
+		for _, instance := range fleet.Instances {
+			if arn, _ := instance.Attributes["arn"].(string); arn != fleetARN {
+				continue
 			}
+			environmentType, _ := instance.Attributes["environment_type"].(string)
+			return "Builds on reserved capacity fleet " + fleet.GetFullAddress() + " (" + environmentType + "); migrate the fleet together with the project"
 		}
 	}
-	return analysis
+	return "Builds on reserved capacity fleet " + fleetARN + "; migrate the fleet together with the project"
 }
 
-func isARM64ComputeType(computeType string) bool {
-	arm64Types := []string{
-		"BUILD_GENERAL1_SMALL_ARM",
-		"BUILD_GENERAL1_MEDIUM_ARM",
-		"BUILD_GENERAL1_LARGE_ARM",
-		"BUILD_GENERAL1_2XLARGE_ARM",
+type CodeBuildFleetAnalyzer struct{}
+
+func (a *CodeBuildFleetAnalyzer) SupportedType() string {
+	return "aws_codebuild_fleet"
+}
+
+func (a *CodeBuildFleetAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
 	}
 
-	return slices.Contains(arm64Types, computeType)
+	for _, instance := range resource.Instances {
+		environmentType, ok := instance.Attributes["environment_type"].(string)
+		if !ok || environmentType == "" {
+			continue
+		}
+
+		if strings.HasPrefix(environmentType, "WINDOWS") {
+			markNotApplicableWindows(&analysis, environmentType)
+			continue
+		}
+
+		if getCodeBuildEnvironmentTypeArch(environmentType) == ArchitectureARM64 {
+			analysis.setArchitecture(ArchitectureARM64)
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "environment_type", "Already using ARM64 environment type "+environmentType)
+		} else if armType, exists := getCodeBuildEnvironmentTypeX86ToArm64Map()[environmentType]; exists {
+			analysis.setArchitecture(ArchitectureX86_64)
+			analysis.RecommendedArch = armType
+			analysis.decide(StatusMigratable, FindingMigratable, "environment_type", "Can migrate fleet to environment type "+armType)
+			analysis.addFinding(FindingNote, "", "Projects using this fleet need arm64 images")
+		} else {
+			analysis.setArchitecture(ArchitectureX86_64)
+			analysis.decide(StatusBlocked, FindingNoARM64Option, "environment_type", "No ARM64 environment type available for "+environmentType)
+		}
+	}
+	return analysis
+}
+
+// getCodeBuildFleetARN returns the fleet_arn of an environment's fleet block.
+func getCodeBuildFleetARN(env map[string]any) string {
+	fleets, ok := env["fleet"].([]any)
+	if !ok || len(fleets) == 0 {
+		return ""
+	}
+	fleet, ok := fleets[0].(map[string]any)
+	if !ok {
+		return ""
+	}
+	fleetARN, _ := fleet["fleet_arn"].(string)
+	return fleetARN
 }
 
-func hasARM64ComputeTypeAlternative(computeType string) bool {
-	_, exists := getX86ToArm64ComputeTypeMap()[computeType]
-	return exists
+// getCodeBuildComputeTypeMismatch reports Lambda compute types used with
+// container environment types and vice versa.
+func getCodeBuildComputeTypeMismatch(environmentType, computeType string) string {
+	if environmentType == "" || computeType == "" {
+		return ""
+	}
+	lambdaEnvironment := strings.HasSuffix(environmentType, "_LAMBDA_CONTAINER")
+	lambdaCompute := strings.HasPrefix(computeType, "BUILD_LAMBDA_")
+	if lambdaEnvironment != lambdaCompute {
+		return "compute_type " + computeType + " cannot be used with environment type " + environmentType
+	}
+	return ""
 }
 
-func getARM64ComputeTypeAlternative(computeType string) string {
-	return getX86ToArm64ComputeTypeMap()[computeType]
+func getCodeBuildEnvironmentTypeArch(environmentType string) Architecture {
+	switch environmentType {
+	case "ARM_CONTAINER", "ARM_LAMBDA_CONTAINER", "ARM_EC2", "MAC_ARM":
+		return ArchitectureARM64
+	case "":
+		return ArchitectureUnknown
+	default:
+		return ArchitectureX86_64
+	}
 }
 
-func getX86ToArm64ComputeTypeMap() map[string]string {
+func getCodeBuildEnvironmentTypeX86ToArm64Map() map[string]string {
 	return map[string]string{
-		"BUILD_GENERAL1_SMALL":   "BUILD_GENERAL1_SMALL_ARM",
-		"BUILD_GENERAL1_MEDIUM":  "BUILD_GENERAL1_MEDIUM_ARM",
-		"BUILD_GENERAL1_LARGE":   "BUILD_GENERAL1_LARGE_ARM",
-		"BUILD_GENERAL1_2XLARGE": "BUILD_GENERAL1_2XLARGE_ARM",
+		"LINUX_CONTAINER":        "ARM_CONTAINER",
+		"LINUX_LAMBDA_CONTAINER": "ARM_LAMBDA_CONTAINER",
+		"LINUX_EC2":              "ARM_EC2",
+	}
+}
+
+// getCodeBuildARM64Image returns the curated aarch64 image of the same OS
+// family as an x86_64 curated image, or an empty string for custom images.
+// CodeBuild has no Ubuntu aarch64 image, so Ubuntu images map to Amazon Linux
+// 2023 and changesOS is set.
+func getCodeBuildARM64Image(image, armType string) (armImage string, changesOS bool) {
+	switch {
+	case strings.HasPrefix(image, "aws/codebuild/amazonlinux-x86_64-lambda-standard:"):
+		return strings.Replace(image, "x86_64", "aarch64", 1), false
+	case armType == "ARM_LAMBDA_CONTAINER":
+		// Lambda images are tagged by runtime; without one to carry over,
+		// suggest the Node.js image
+		return "aws/codebuild/amazonlinux-aarch64-lambda-standard:nodejs20", false
+	case strings.HasPrefix(image, "aws/codebuild/amazonlinux2-x86_64-standard:"):
+		return "aws/codebuild/amazonlinux2-aarch64-standard:3.0", false
+	case strings.HasPrefix(image, "aws/codebuild/amazonlinux-x86_64-standard:"):
+		_, version, _ := strings.Cut(image, ":")
+		return "aws/codebuild/amazonlinux-aarch64-standard:" + getCodeBuildAL2023ARM64ImageVersion(version), false
+	case strings.HasPrefix(image, "aws/codebuild/standard:"):
+		return "aws/codebuild/amazonlinux-aarch64-standard:3.0", true
+	default:
+		return "", false
 	}
 }
+
+// getCodeBuildAL2023ARM64ImageVersion returns the Amazon Linux 2023 aarch64
+// standard image version that ships the same runtimes as an x86_64 one; the
+// two are versioned separately. Unknown versions get the newest image.
+func getCodeBuildAL2023ARM64ImageVersion(version string) string {
+	versions := map[string]string{
+		"4.0": "2.0",
+		"5.0": "3.0",
+	}
+	if armVersion, found := versions[version]; found {
+		return armVersion
+	}
+	return "3.0"
+}
+
+// isARM64ComputeType recognises the legacy "_ARM" compute type spellings.
+func isARM64ComputeType(computeType string) bool {
+	arm64Types := []string{
+		"BUILD_GENERAL1_SMALL_ARM",
+		"BUILD_GENERAL1_MEDIUM_ARM",
+		"BUILD_GENERAL1_LARGE_ARM",
+		"BUILD_GENERAL1_2XLARGE_ARM",
+	}
+
+	return slices.Contains(arm64Types, computeType)
+}
@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestCodeBuildAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name            string
+		environment     map[string]interface{}
+		expectARM64     bool
+		expectUsing     bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name: "ARM container with aarch64 image",
+			environment: map[string]interface{}{
+				"type":         "ARM_CONTAINER",
+				"image":        "aws/codebuild/amazonlinux2-aarch64-standard:3.0",
+				"compute_type": "BUILD_GENERAL1_LARGE",
+			},
+			expectARM64:     true,
+			expectUsing:     true,
+			expectRecommend: "ARM64",
+			expectNotes:     "Already using ARM64 environment type ARM_CONTAINER",
+		},
+		{
+			name: "ARM container with x86 image is misconfigured",
+			environment: map[string]interface{}{
+				"type":         "ARM_CONTAINER",
+				"image":        "aws/codebuild/amazonlinux2-x86_64-standard:5.0",
+				"compute_type": "BUILD_GENERAL1_SMALL",
+			},
+			expectARM64:     false,
+			expectRecommend: "ARM64",
+			expectNotes:     "Misconfigured: ARM_CONTAINER with x86_64 image",
+		},
+		{
+			name: "Linux container with curated image",
+			environment: map[string]interface{}{
+				"type":         "LINUX_CONTAINER",
+				"image":        "aws/codebuild/standard:7.0",
+				"compute_type": "BUILD_GENERAL1_MEDIUM",
+			},
+			expectARM64:     true,
+			expectRecommend: "ARM_CONTAINER",
+			expectNotes:     "with image aws/codebuild/amazonlinux-aarch64-standard:3.0 | Prerequisite: No aarch64 curated image for aws/codebuild/standard:7.0; the build moves from Ubuntu to Amazon Linux 2023",
+		},
+		{
+			name: "Linux container with Amazon Linux 2 image",
+			environment: map[string]interface{}{
+				"type":         "LINUX_CONTAINER",
+				"image":        "aws/codebuild/amazonlinux2-x86_64-standard:5.0",
+				"compute_type": "BUILD_GENERAL1_MEDIUM",
+			},
+			expectARM64:     true,
+			expectRecommend: "ARM_CONTAINER",
+			expectNotes:     "with image aws/codebuild/amazonlinux2-aarch64-standard:3.0",
+		},
+		{
+			name: "Linux container with Amazon Linux 2023 image",
+			environment: map[string]interface{}{
+				"type":         "LINUX_CONTAINER",
+				"image":        "aws/codebuild/amazonlinux-x86_64-standard:4.0",
+				"compute_type": "BUILD_GENERAL1_MEDIUM",
+			},
+			expectARM64:     true,
+			expectRecommend: "ARM_CONTAINER",
+			expectNotes:     "with image aws/codebuild/amazonlinux-aarch64-standard:2.0",
+		},
+		{
+			name: "Lambda compute on x86",
+			environment: map[string]interface{}{
+				"type":         "LINUX_LAMBDA_CONTAINER",
+				"image":        "aws/codebuild/amazonlinux-x86_64-lambda-standard:nodejs20",
+				"compute_type": "BUILD_LAMBDA_1GB",
+			},
+			expectARM64:     true,
+			expectRecommend: "ARM_LAMBDA_CONTAINER",
+			expectNotes:     "aws/codebuild/amazonlinux-aarch64-lambda-standard:nodejs20",
+		},
+		{
+			name: "Lambda compute without a curated image",
+			environment: map[string]interface{}{
+				"type":         "LINUX_LAMBDA_CONTAINER",
+				"compute_type": "BUILD_LAMBDA_1GB",
+			},
+			expectARM64:     true,
+			expectRecommend: "ARM_LAMBDA_CONTAINER",
+			expectNotes:     "with image aws/codebuild/amazonlinux-aarch64-lambda-standard:nodejs20",
+		},
+		{
+			name: "environment type not in state",
+			environment: map[string]interface{}{
+				"image":        "aws/codebuild/standard:7.0",
+				"compute_type": "BUILD_GENERAL1_MEDIUM",
+			},
+			expectARM64: false,
+			expectNotes: "environment type not in state",
+		},
+		{
+			name: "Lambda compute type with container environment",
+			environment: map[string]interface{}{
+				"type":         "ARM_CONTAINER",
+				"image":        "aws/codebuild/amazonlinux2-aarch64-standard:3.0",
+				"compute_type": "BUILD_LAMBDA_1GB",
+			},
+			expectARM64: false,
+			expectNotes: "Misconfigured: compute_type BUILD_LAMBDA_1GB",
+		},
+		{
+			name: "GPU container has no ARM64 option",
+			environment: map[string]interface{}{
+				"type":         "LINUX_GPU_CONTAINER",
+				"image":        "aws/codebuild/standard:7.0",
+				"compute_type": "BUILD_GENERAL1_LARGE",
+			},
+			expectARM64: false,
+			expectNotes: "No ARM64 environment type available for LINUX_GPU_CONTAINER",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &CodeBuildAnalyzer{}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type: "aws_codebuild_project",
+				Name: "build",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"environment": []any{tt.environment},
+						},
+					},
+				},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+			if !strings.Contains(tt.expectNotes, "Prerequisite") && strings.Contains(analysis.Notes, "Prerequisite") {
+				t.Errorf("Analyze() Notes = %q, want no prerequisite", analysis.Notes)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type DocumentDBInstanceAnalyzer struct {
+	ctx *Context
+}
+
+func (a *DocumentDBInstanceAnalyzer) SupportedType() string {
+	return "aws_docdb_cluster_instance"
+}
+
+func (a *DocumentDBInstanceAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	return analyzeDBClusterInstance(a.ctx, resource, dbClusterEngine{
+		Name:         "docdb",
+		ClusterType:  "aws_docdb_cluster",
+		ARM64Classes: getDocumentDBARM64InstanceClasses(),
+		Minimums:     getDocumentDBEngineMinimums(),
+	})
+}
+
+// dbClusterEngine describes an engine, such as DocumentDB or Neptune, whose
+// cluster instances use RDS instance classes but only a subset of the
+// Graviton ones.
+type dbClusterEngine struct {
+	Name        string
+	ClusterType string
+	// ARM64Classes lists the Graviton instance classes the engine offers
+	ARM64Classes []string
+	// Minimums lists the minimum engine version on each release line that
+	// supports Graviton instance classes
+	Minimums []string
+}
+
+// analyzeDBClusterInstance makes the Graviton decision for a cluster instance
+// of an RDS-family engine. Instances may omit engine_version, in which case
+// that of their cluster applies. Serverless instances have no instance class
+// to migrate.
+func analyzeDBClusterInstance(ctx *Context, resource parser.TerraformResource, engine dbClusterEngine) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		instanceClass, ok := instance.Attributes["instance_class"].(string)
+		if !ok {
+			continue
+		}
+
+		if instanceClass == "db.serverless" {
+			analysis.setArchitecture(ArchitectureManaged)
+			analysis.decide(StatusNotApplicable, FindingNotApplicable, "instance_class", "serverless (db.serverless) capacity is managed by AWS")
+			continue
+		}
+
+		if slices.Contains(engine.ARM64Classes, instanceClass) {
+			analysis.setArchitecture(ArchitectureARM64)
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "instance_class", "Already using ARM64 instance class")
+		} else if alternative := getARM64DBClusterAlternative(instanceClass, engine.ARM64Classes); alternative != "" {
+			analysis.setArchitecture(ArchitectureX86_64)
+			analysis.RecommendedArch = alternative
+			analysis.decide(StatusMigratable, FindingMigratable, "instance_class", "Can migrate to ARM64 instance class: "+alternative)
+
+			value, _, _ := ctx.lookupAttribute(resource, instance.Attributes, "engine_version")
+			engineVersion, _ := value.(string)
+			if engineVersion == "" {
+				clusterIdentifier, _ := instance.Attributes["cluster_identifier"].(string)
+				engineVersion = ctx.findClusterEngineVersion(engine.ClusterType, clusterIdentifier)
+			}
+			applyEngineVersionMinimum(&analysis, engine.Name, engineVersion, engine.Minimums)
+		} else {
+			analysis.setArchitecture(ArchitectureX86_64)
+			analysis.decide(StatusBlocked, FindingNoARM64Option, "instance_class", "No ARM64 compatible instance class available")
+		}
+	}
+	return analysis
+}
+
+// getARM64DBClusterAlternative maps an instance class through the RDS class
+// table onto the Graviton classes an engine offers, stepping down to the
+// Graviton2 class of the same family and size when the engine lacks the
+// newer one, e.g. "db.r5.large" -> "db.r7g.large" -> "db.r6g.large".
+func getARM64DBClusterAlternative(instanceClass string, arm64Classes []string) string {
+	alternative := getARM64RDSAlternative(instanceClass)
+	if alternative == "" {
+		return ""
+	}
+	for _, candidate := range []string{alternative, strings.Replace(alternative, "7g.", "6g.", 1)} {
+		if slices.Contains(arm64Classes, candidate) {
+			return candidate
+		}
+	}
+	return ""
+}
+
+// findClusterEngineVersion returns the engine_version of the cluster with the
+// given identifier, or an empty string when it is not in the state.
+func (c *Context) findClusterEngineVersion(clusterType, clusterIdentifier string) string {
+	if clusterIdentifier == "" {
+		return ""
+	}
+	for _, cluster := range c.FindResources(clusterType) {
+		for _, instance := range cluster.Instances {
+			if id, _ := instance.Attributes["cluster_identifier"].(string); id != clusterIdentifier {
+				continue
+			}
+			engineVersion, _, _ := c.lookupAttribute(cluster, instance.Attributes, "engine_version")
+			engineVersionStr, _ := engineVersion.(string)
+			return engineVersionStr
+		}
+	}
+	return ""
+}
+
+// getDocumentDBEngineMinimums lists the minimum engine version on each release
+// line that supports Graviton instance classes; 3.6 does not.
+func getDocumentDBEngineMinimums() []string {
+	return []string{"4.0.0"}
+}
+
+func getDocumentDBARM64InstanceClasses() []string {
+	return []string{
+		// Graviton2
+		"db.t4g.medium",
+		"db.r6g.large", "db.r6g.xlarge", "db.r6g.2xlarge", "db.r6g.4xlarge", "db.r6g.8xlarge", "db.r6g.12xlarge", "db.r6g.16xlarge",
+		"db.r6gd.xlarge", "db.r6gd.2xlarge", "db.r6gd.4xlarge", "db.r6gd.8xlarge", "db.r6gd.12xlarge", "db.r6gd.16xlarge",
+	}
+}
@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestDocumentDBInstanceAnalyzer_Analyze(t *testing.T) {
+	cluster := func(engineVersion string) parser.TerraformResource {
+		return parser.TerraformResource{
+			Mode: "managed",
+			Type: "aws_docdb_cluster",
+			Name: "docs",
+			Instances: []parser.ResourceInstance{
+				{
+					Attributes: map[string]interface{}{
+						"cluster_identifier": "docs",
+						"engine_version":     engineVersion,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name            string
+		instanceClass   string
+		engineVersion   string
+		expectARM64     bool
+		expectUsing     bool
+		expectNotApp    bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name:          "Graviton class",
+			instanceClass: "db.r6g.large",
+			engineVersion: "5.0.0",
+			expectARM64:   true,
+			expectUsing:   true,
+			expectNotes:   "Already using ARM64 instance class",
+		},
+		{
+			name:            "x86 class on an eligible engine",
+			instanceClass:   "db.r5.xlarge",
+			engineVersion:   "5.0.0",
+			expectARM64:     true,
+			expectRecommend: "db.r6g.xlarge",
+			expectNotes:     "Can migrate to ARM64 instance class: db.r6g.xlarge",
+		},
+		{
+			name:            "3.6 cluster needs an engine upgrade",
+			instanceClass:   "db.t3.medium",
+			engineVersion:   "3.6.0",
+			expectARM64:     true,
+			expectRecommend: "db.t4g.medium",
+			expectNotes:     "Prerequisite: upgrade engine_version 3.6.0 -> 4.0.0",
+		},
+		{
+			name:          "class without an ARM64 alternative",
+			instanceClass: "db.r5.24xlarge",
+			engineVersion: "5.0.0",
+			expectARM64:   false,
+			expectNotes:   "No ARM64 compatible instance class available",
+		},
+		{
+			name:          "serverless instance",
+			instanceClass: "db.serverless",
+			engineVersion: "5.0.0",
+			expectNotApp:  true,
+			expectNotes:   "Not applicable: serverless (db.serverless) capacity is managed by AWS",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &parser.TerraformState{Version: 4, Resources: []parser.TerraformResource{cluster(tt.engineVersion)}}
+			analyzer := &DocumentDBInstanceAnalyzer{ctx: NewContext(state)}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type: "aws_docdb_cluster_instance",
+				Name: "docs",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"cluster_identifier": "docs",
+							"instance_class":     tt.instanceClass,
+						},
+					},
+				},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if analysis.NotApplicable != tt.expectNotApp {
+				t.Errorf("Analyze() NotApplicable = %v, want %v", analysis.NotApplicable, tt.expectNotApp)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
+
+func TestGetARM64DBClusterAlternative(t *testing.T) {
+	tests := []struct {
+		instanceClass string
+		arm64Classes  []string
+		expected      string
+	}{
+		{"db.r5.large", getDocumentDBARM64InstanceClasses(), "db.r6g.large"},
+		{"db.r4.2xlarge", getNeptuneARM64InstanceClasses(), "db.r6g.2xlarge"},
+		{"db.t3.medium", getNeptuneARM64InstanceClasses(), "db.t4g.medium"},
+		{"db.m5.large", getDocumentDBARM64InstanceClasses(), ""},
+		{"db.x1.16xlarge", getDocumentDBARM64InstanceClasses(), ""},
+	}
+
+	for _, tt := range tests {
+		if got := getARM64DBClusterAlternative(tt.instanceClass, tt.arm64Classes); got != tt.expected {
+			t.Errorf("getARM64DBClusterAlternative(%q) = %q, want %q", tt.instanceClass, got, tt.expected)
+		}
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"github.com/suer/tf-arm/internal/parser"
 )
 
-type EC2Analyzer struct{}
+type EC2Analyzer struct {
+	ctx *Context
+}
 
 func (a *EC2Analyzer) SupportedType() string {
 	return "aws_instance"
@@ -21,31 +23,39 @@ func (a *EC2Analyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
 	}
 
 	for _, instance := range resource.Instances {
+		if platform, ok := instance.Attributes["platform"].(string); ok && isWindowsOperatingSystem(platform) {
+			markNotApplicableWindows(&analysis, platform)
+			continue
+		}
+
 		if instanceType, exists := instance.Attributes["instance_type"]; exists {
 			instanceTypeStr, ok := instanceType.(string)
 			if !ok {
 				continue
 			}
-			analysis.CurrentArch = getArchFromInstanceType(instanceTypeStr)
+			analysis.setArchitecture(getArchFromInstanceType(instanceTypeStr))
 
 			if isARM64InstanceType(instanceTypeStr) {
-				analysis.ARM64Compatible = true
-				analysis.AlreadyUsingARM64 = true
 				analysis.RecommendedArch = "ARM64"
-				analysis.Notes = "Already using ARM64 instance type"
+				analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "instance_type", "Already using ARM64 instance type")
 			} else if hasARM64Alternative(instanceTypeStr) {
-				analysis.ARM64Compatible = true
 				analysis.RecommendedArch = getARM64Alternative(instanceTypeStr)
-				analysis.Notes = fmt.Sprintf("Can migrate to ARM64 instance type %s", analysis.RecommendedArch)
+				analysis.decide(StatusMigratable, FindingMigratable, "instance_type", fmt.Sprintf("Can migrate to ARM64 instance type %s", analysis.RecommendedArch))
+				ami, _ := instance.Attributes["ami"].(string)
+				if goldenImage, found := a.ctx.describeGoldenImage(ami, "ami"); found {
+					analysis.recordFinding(goldenImage)
+				}
 			} else {
-				analysis.Notes = "No ARM64 compatible instance type available"
+				analysis.decide(StatusBlocked, FindingNoARM64Option, "instance_type", "No ARM64 compatible instance type available")
 			}
 		}
 	}
 	return analysis
 }
 
-type LaunchTemplateAnalyzer struct{}
+type LaunchTemplateAnalyzer struct {
+	ctx *Context
+}
 
 func (a *LaunchTemplateAnalyzer) SupportedType() string {
 	return "aws_launch_template"
@@ -64,17 +74,18 @@ func (a *LaunchTemplateAnalyzer) Analyze(resource parser.TerraformResource) ARM6
 			if !ok {
 				continue
 			}
-			analysis.CurrentArch = getArchFromInstanceType(instanceTypeStr)
+			analysis.setArchitecture(getArchFromInstanceType(instanceTypeStr))
 
 			if isARM64InstanceType(instanceTypeStr) {
-				analysis.ARM64Compatible = true
-				analysis.AlreadyUsingARM64 = true
 				analysis.RecommendedArch = "ARM64"
-				analysis.Notes = "Already using ARM64 instance type"
+				analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "instance_type", "Already using ARM64 instance type")
 			} else if hasARM64Alternative(instanceTypeStr) {
-				analysis.ARM64Compatible = true
 				analysis.RecommendedArch = getARM64Alternative(instanceTypeStr)
-				analysis.Notes = fmt.Sprintf("Can migrate to ARM64 instance type %s", analysis.RecommendedArch)
+				analysis.decide(StatusMigratable, FindingMigratable, "instance_type", fmt.Sprintf("Can migrate to ARM64 instance type %s", analysis.RecommendedArch))
+				imageID, _ := instance.Attributes["image_id"].(string)
+				if goldenImage, found := a.ctx.describeGoldenImage(imageID, "image_id"); found {
+					analysis.recordFinding(goldenImage)
+				}
 			}
 		}
 	}
@@ -109,11 +120,11 @@ func getARM64Alternative(instanceType string) string {
 	return getX86ToArm64Map()[instanceType]
 }
 
-func getArchFromInstanceType(instanceType string) string {
+func getArchFromInstanceType(instanceType string) Architecture {
 	if isARM64InstanceType(instanceType) {
-		return "ARM64"
+		return ArchitectureARM64
 	}
-	return "X86_64"
+	return ArchitectureX86_64
 }
 
 func getX86ToArm64Map() map[string]string {
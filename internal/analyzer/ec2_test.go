@@ -109,6 +109,24 @@ func TestEC2Analyzer_Analyze(t *testing.T) {
 			expectUsing: false,
 			expectNotes: "No ARM64 compatible instance type available",
 		},
+		{
+			name: "windows instance is not applicable",
+			resource: parser.TerraformResource{
+				Type: "aws_instance",
+				Name: "windows",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"instance_type": "m5.large",
+							"platform":      "windows",
+						},
+					},
+				},
+			},
+			expectARM64: false,
+			expectUsing: false,
+			expectNotes: "Not applicable: Windows",
+		},
 		{
 			name: "resource without instance_type",
 			resource: parser.TerraformResource{
@@ -365,22 +383,22 @@ func TestGetARM64Alternative(t *testing.T) {
 func TestGetArchFromInstanceType(t *testing.T) {
 	tests := []struct {
 		instanceType string
-		expected     string
+		expected     Architecture
 	}{
 		// ARM64 instances
-		{"t4g.micro", "ARM64"},
-		{"m6g.large", "ARM64"},
-		{"c6g.xlarge", "ARM64"},
-		{"m7g.medium", "ARM64"},
-		{"c7g.large", "ARM64"},
-		{"r7g.xlarge", "ARM64"},
-		{"c8g.large", "ARM64"},
-		{"m8g.xlarge", "ARM64"},
-		{"r8g.2xlarge", "ARM64"},
+		{"t4g.micro", ArchitectureARM64},
+		{"m6g.large", ArchitectureARM64},
+		{"c6g.xlarge", ArchitectureARM64},
+		{"m7g.medium", ArchitectureARM64},
+		{"c7g.large", ArchitectureARM64},
+		{"r7g.xlarge", ArchitectureARM64},
+		{"c8g.large", ArchitectureARM64},
+		{"m8g.xlarge", ArchitectureARM64},
+		{"r8g.2xlarge", ArchitectureARM64},
 		// X86_64 instances
-		{"t3.micro", "X86_64"},
-		{"m5.large", "X86_64"},
-		{"c5.xlarge", "X86_64"},
+		{"t3.micro", ArchitectureX86_64},
+		{"m5.large", ArchitectureX86_64},
+		{"c5.xlarge", ArchitectureX86_64},
 	}
 
 	for _, tt := range tests {
@@ -1,8 +1,17 @@
 package analyzer
 
-import "github.com/suer/tf-arm/internal/parser"
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
 
-type ECSAnalyzer struct{}
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type ECSAnalyzer struct {
+	ctx *Context
+}
 
 func (a *ECSAnalyzer) SupportedType() string {
 	return "aws_ecs_task_definition"
@@ -12,26 +21,328 @@ func (a *ECSAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
 	analysis := ARM64Analysis{
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
-		ARM64Compatible: true,
+		ARM64Compatible: false,
 	}
 
 	for _, instance := range resource.Instances {
-		if cpuArch, exists := instance.Attributes["cpu_architecture"]; exists {
+		osFamily, _, _ := a.ctx.lookupAttribute(resource, instance.Attributes, "operating_system_family")
+		if osFamilyStr, ok := osFamily.(string); ok && isWindowsOperatingSystem(osFamilyStr) {
+			markNotApplicableWindows(&analysis, osFamilyStr)
+			continue
+		}
+
+		if cpuArch, path, exists := a.ctx.lookupAttribute(resource, instance.Attributes, "cpu_architecture"); exists && cpuArch != "" {
 			if cpuArch == "ARM64" {
-				analysis.CurrentArch = "ARM64"
-				analysis.AlreadyUsingARM64 = true
+				analysis.setArchitecture(ArchitectureARM64)
 				analysis.RecommendedArch = "ARM64"
-				analysis.Notes = "Already using ARM64 architecture"
+				analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, path, "Already using ARM64 architecture")
 			} else {
-				analysis.CurrentArch = "X86_64"
+				analysis.setArchitecture(ArchitectureX86_64)
 				analysis.RecommendedArch = "ARM64"
-				analysis.Notes = "Can change cpu_architecture to ARM64"
+				analysis.decide(StatusMigratable, FindingMigratable, path, "Can set runtime_platform { cpu_architecture = \"ARM64\" }")
 			}
+		} else if !a.ctx.attributeAvailable(resource, "cpu_architecture") {
+			analysis.setDefaultArchitecture(ArchitectureX86_64)
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusMigratable, FindingPrerequisite, "", "Upgrade the AWS provider to v3.70 or later to set runtime_platform { cpu_architecture = \"ARM64\" }")
 		} else {
-			analysis.CurrentArch = "X86_64 (default)"
+			analysis.setDefaultArchitecture(ArchitectureX86_64)
 			analysis.RecommendedArch = "ARM64"
-			analysis.Notes = "Can add cpu_architecture = \"ARM64\""
+			analysis.decide(StatusMigratable, FindingMigratable, "runtime_platform", "Can add runtime_platform { cpu_architecture = \"ARM64\" }")
+		}
+
+		containers, err := parseContainerDefinitions(instance.Attributes["container_definitions"])
+		if err != nil {
+			analysis.addFinding(FindingUnverified, "container_definitions", "Could not parse container_definitions: "+err.Error())
+			continue
+		}
+		if len(containers) == 0 {
+			continue
+		}
+
+		var results, statuses []string
+		var blocked []string
+		for _, container := range containers {
+			status := a.ctx.checkContainerImage(container.Image)
+			results = append(results, fmt.Sprintf("%s (%s): %s", container.Name, container.Image, status))
+			statuses = append(statuses, status)
+			if status == containerImageX86Only {
+				blocked = append(blocked, container.Name)
+			}
 		}
+
+		if len(blocked) > 0 {
+			if analysis.Status == StatusAlreadyARM64 {
+				analysis.decide(StatusMisconfigured, FindingMisconfigured, "container_definitions", "Using ARM64 but containers lack an arm64 image: "+strings.Join(blocked, ", "))
+			} else {
+				analysis.RecommendedArch = ""
+				analysis.decide(StatusBlocked, FindingBlocked, "container_definitions", "containers without an arm64 image: "+strings.Join(blocked, ", "))
+			}
+		}
+		analysis.addFinding(getImageFindingCode(statuses), "container_definitions", "Containers: "+strings.Join(results, "; "))
 	}
 	return analysis
 }
+
+const (
+	containerImageARM64     = "arm64 available"
+	containerImageX86Only   = "no arm64 variant"
+	containerImageUnknown   = "not in image cache"
+	containerImageUnchecked = "not verified"
+)
+
+// checkContainerImage reports whether a container image has an arm64 variant
+// in the image cache.
+func (c *Context) checkContainerImage(image string) string {
+	if c == nil || c.Images == nil {
+		return containerImageUnchecked
+	}
+	architectures, found := c.ImageArchitectures(image)
+	switch {
+	case !found:
+		return containerImageUnknown
+	case slices.Contains(architectures, "arm64"):
+		return containerImageARM64
+	default:
+		return containerImageX86Only
+	}
+}
+
+// getImageFindingCode returns the code of the finding listing image checks:
+// unverified when any image could not be inspected.
+func getImageFindingCode(statuses []string) FindingCode {
+	for _, status := range statuses {
+		if status == containerImageUnknown || status == containerImageUnchecked {
+			return FindingUnverified
+		}
+	}
+	return FindingNote
+}
+
+type containerDefinition struct {
+	Name  string `json:"name"`
+	Image string `json:"image"`
+}
+
+// parseContainerDefinitions decodes the container_definitions attribute,
+// which the AWS provider stores as a JSON-encoded string.
+func parseContainerDefinitions(value any) ([]containerDefinition, error) {
+	definitions, ok := value.(string)
+	if !ok || definitions == "" {
+		return nil, nil
+	}
+
+	var containers []containerDefinition
+	if err := json.Unmarshal([]byte(definitions), &containers); err != nil {
+		return nil, err
+	}
+	return containers, nil
+}
+
+type ECSServiceAnalyzer struct {
+	ctx *Context
+}
+
+func (a *ECSServiceAnalyzer) SupportedType() string {
+	return "aws_ecs_service"
+}
+
+func (a *ECSServiceAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+		RecommendedArch: "ARM64",
+	}
+	analysis.setDefaultArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		taskDefinition, _ := instance.Attributes["task_definition"].(string)
+		task := a.findTaskDefinition(taskDefinition)
+
+		launchType, _ := instance.Attributes["launch_type"].(string)
+		providers := a.getCapacityProviders(instance.Attributes)
+		switch {
+		case launchType == "EXTERNAL":
+			analysis.setArchitecture(ArchitectureExternal)
+			analysis.RecommendedArch = ""
+			analysis.decide(StatusNotApplicable, FindingNotApplicable, "launch_type", "EXTERNAL launch type runs on instances registered outside AWS")
+		case launchType == "FARGATE" || (launchType == "" && len(providers) > 0 && !slices.ContainsFunc(providers, func(p string) bool { return !isFargateCapacityProvider(p) })):
+			a.analyzeFargate(&analysis, task)
+		case launchType == "" && len(providers) == 0:
+			markUnknown(&analysis, "launch_type", "Service configuration unclear for ARM64 compatibility")
+		default:
+			a.analyzeEC2(&analysis, task, providers)
+		}
+	}
+	return analysis
+}
+
+func (a *ECSServiceAnalyzer) analyzeFargate(analysis *ARM64Analysis, task ecsServiceTask) {
+	switch {
+	case !task.Found:
+		analysis.decide(StatusMigratable, FindingMigratable, "task_definition", "Fargate supports ARM64")
+		analysis.addFinding(FindingMissingAttribute, "task_definition", "Task definition not found in state; check its cpu_architecture")
+	case task.Arch == ArchitectureARM64:
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "task_definition", "Already using ARM64 on Fargate through "+task.Address)
+	default:
+		analysis.decide(StatusMigratable, FindingMigratable, "task_definition", "Fargate supports ARM64")
+		analysis.addFinding(FindingNote, "task_definition", "Can set cpu_architecture to ARM64 in "+task.Address)
+	}
+}
+
+// analyzeEC2 compares the architecture of the EC2 capacity a service is
+// placed on with the cpu_architecture of its task definition; tasks cannot be
+// placed when the two disagree.
+func (a *ECSServiceAnalyzer) analyzeEC2(analysis *ARM64Analysis, task ecsServiceTask, providers []string) {
+	var details, blocked, missing []string
+	var capacityArch Architecture
+	for _, provider := range providers {
+		if isFargateCapacityProvider(provider) {
+			continue
+		}
+		capacity, found := a.ctx.analyzeCapacityProvider(provider)
+		if !found {
+			details = append(details, provider+" (not found in state)")
+			missing = append(missing, provider)
+			continue
+		}
+		details = append(details, provider+" ("+capacity.CurrentArch+")")
+		if capacity.Status != StatusAlreadyARM64 && !capacity.canMigrate() {
+			blocked = append(blocked, provider)
+		}
+		switch {
+		case capacityArch == "":
+			capacityArch = capacity.Architecture
+		case capacityArch != capacity.Architecture:
+			capacityArch = ArchitectureMixed
+		}
+	}
+
+	taskNote := "task definition not found in state"
+	if task.Found {
+		taskNote = task.Address + " cpu_architecture is " + getLegacyArchitectures()[task.Arch]
+	}
+	capacityNote := "EC2 capacity: " + strings.Join(details, ", ")
+	if len(details) == 0 {
+		capacityNote = "EC2 capacity not found in state; verify the container instances' architecture"
+	}
+
+	const attribute = "capacity_provider_strategy"
+	capacityCode := FindingNote
+	if len(missing) > 0 {
+		capacityCode = FindingMissingAttribute
+	}
+	switch {
+	case capacityArch == "":
+		markUnknown(analysis, attribute, capacityNote)
+	case task.Found && capacityArch != ArchitectureMixed && capacityArch != task.Arch:
+		analysis.setArchitecture(ArchitectureMixed)
+		analysis.RecommendedArch = ""
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, "task_definition", taskNote+" but its capacity is "+getLegacyArchitectures()[capacityArch]+"; tasks cannot be placed")
+		analysis.addFinding(capacityCode, attribute, capacityNote)
+		return
+	case capacityArch == ArchitectureARM64:
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, attribute, "Already using Graviton capacity")
+		analysis.addFinding(capacityCode, attribute, capacityNote)
+	case len(blocked) > 0:
+		analysis.RecommendedArch = ""
+		analysis.decide(StatusBlocked, FindingNoARM64Option, attribute, "No ARM64 option for capacity provider "+strings.Join(blocked, ", "))
+		analysis.addFinding(capacityCode, attribute, capacityNote)
+		return
+	case capacityArch == ArchitectureMixed:
+		analysis.setArchitecture(ArchitectureMixed)
+		analysis.decide(StatusPartiallyMigrated, FindingPartiallyMigrated, attribute, "Capacity mixes architectures; constrain placement on ecs.cpu-architecture")
+		analysis.addFinding(capacityCode, attribute, capacityNote)
+	default:
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.decide(StatusMigratable, FindingMigratable, attribute, "Can migrate capacity providers to Graviton together with cpu_architecture = \"ARM64\"")
+		analysis.addFinding(capacityCode, attribute, capacityNote)
+	}
+	if task.Found {
+		analysis.addFinding(FindingNote, "task_definition", taskNote)
+	} else {
+		analysis.addFinding(FindingMissingAttribute, "task_definition", taskNote)
+	}
+}
+
+// getCapacityProviders returns the capacity providers a service's tasks are
+// placed on: its own strategy, or the cluster's default strategy when it
+// has neither a strategy nor a launch type. EC2 launch type services run on
+// any container instance in the cluster, so every capacity provider of the
+// cluster is considered.
+func (a *ECSServiceAnalyzer) getCapacityProviders(attributes map[string]any) []string {
+	var providers []string
+	strategies, _ := attributes["capacity_provider_strategy"].([]any)
+	for _, strategy := range strategies {
+		if strategyMap, ok := strategy.(map[string]any); ok {
+			if provider, ok := strategyMap["capacity_provider"].(string); ok {
+				providers = append(providers, provider)
+			}
+		}
+	}
+	if len(providers) > 0 {
+		return providers
+	}
+
+	launchType, _ := attributes["launch_type"].(string)
+	cluster, _ := attributes["cluster"].(string)
+	for _, resource := range a.ctx.FindResources("aws_ecs_cluster_capacity_providers") {
+		for _, instance := range resource.Instances {
+			if name, _ := instance.Attributes["cluster_name"].(string); name != getECSClusterName(cluster) {
+				continue
+			}
+			if launchType == "EC2" {
+				return getStringList(instance.Attributes["capacity_providers"])
+			}
+			if launchType != "" {
+				return nil
+			}
+			defaults, _ := instance.Attributes["default_capacity_provider_strategy"].([]any)
+			for _, strategy := range defaults {
+				if strategyMap, ok := strategy.(map[string]any); ok {
+					if provider, ok := strategyMap["capacity_provider"].(string); ok {
+						providers = append(providers, provider)
+					}
+				}
+			}
+			return providers
+		}
+	}
+	return nil
+}
+
+type ecsServiceTask struct {
+	Found   bool
+	Address string
+	Arch    Architecture
+}
+
+// findTaskDefinition resolves a service's task_definition, which may be a
+// full ARN, "family:revision" or a family name.
+func (a *ECSServiceAnalyzer) findTaskDefinition(reference string) ecsServiceTask {
+	if reference == "" {
+		return ecsServiceTask{}
+	}
+	for _, resource := range a.ctx.FindResources("aws_ecs_task_definition") {
+		for _, instance := range resource.Instances {
+			arn, _ := instance.Attributes["arn"].(string)
+			arnWithoutRevision, _ := instance.Attributes["arn_without_revision"].(string)
+			family, _ := instance.Attributes["family"].(string)
+			revision, _ := instance.Attributes["revision"].(float64)
+			if reference != arn && reference != arnWithoutRevision && reference != family &&
+				reference != fmt.Sprintf("%s:%d", family, int(revision)) {
+				continue
+			}
+
+			arch := ArchitectureX86_64
+			if cpuArch, _, exists := a.ctx.lookupAttribute(resource, instance.Attributes, "cpu_architecture"); exists && cpuArch == "ARM64" {
+				arch = ArchitectureARM64
+			}
+			return ecsServiceTask{Found: true, Address: resource.GetFullAddress(), Arch: arch}
+		}
+	}
+	return ecsServiceTask{}
+}
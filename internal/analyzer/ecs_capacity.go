@@ -0,0 +1,150 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type CapacityProviderAnalyzer struct {
+	ctx *Context
+}
+
+func (a *CapacityProviderAnalyzer) SupportedType() string {
+	return "aws_ecs_capacity_provider"
+}
+
+func (a *CapacityProviderAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		provider := getFirstBlock(instance.Attributes["auto_scaling_group_provider"])
+		arn, _ := provider["auto_scaling_group_arn"].(string)
+		group, found := a.ctx.findAutoScalingGroup(arn)
+		if !found {
+			markUnknown(&analysis, "auto_scaling_group_provider.0.auto_scaling_group_arn", "Auto Scaling group "+arn+" not found in state")
+			continue
+		}
+
+		groupAnalysis := (&AutoScalingGroupAnalyzer{ctx: a.ctx}).Analyze(group)
+		analysis.adoptVerdict(groupAnalysis, "auto_scaling_group_provider.0.auto_scaling_group_arn")
+		analysis.addFinding(FindingNote, "auto_scaling_group_provider.0.auto_scaling_group_arn", "Backed by "+group.GetFullAddress())
+		if analysis.canMigrate() {
+			analysis.addFinding(FindingNote, "", "Tasks placed here need task definitions with cpu_architecture = \"ARM64\"")
+		}
+	}
+	return analysis
+}
+
+type ClusterCapacityProvidersAnalyzer struct {
+	ctx *Context
+}
+
+func (a *ClusterCapacityProvidersAnalyzer) SupportedType() string {
+	return "aws_ecs_cluster_capacity_providers"
+}
+
+// Analyze rolls up the capacity providers attached to a cluster. Fargate
+// providers take their architecture from each task definition, so only EC2
+// capacity providers affect the verdict.
+func (a *ClusterCapacityProvidersAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		var findings []roleFinding
+		fargateOnly := true
+		for _, name := range getStringList(instance.Attributes["capacity_providers"]) {
+			capacity, found := a.ctx.analyzeCapacityProvider(name)
+			finding := roleFinding{Role: name, Current: capacity.CurrentArch, Attribute: "capacity_providers"}
+			fargateOnly = fargateOnly && isFargateCapacityProvider(name)
+			switch {
+			case isFargateCapacityProvider(name):
+				finding.Status = roleSkipped
+				finding.Message = "architecture is set by each task definition"
+			case !found:
+				finding.Status = roleSkipped
+				finding.Missing = true
+				finding.Message = "not found in state"
+			case capacity.Status == StatusAlreadyARM64:
+				finding.Status = roleGraviton
+				finding.Message = "already ARM64"
+			case capacity.canMigrate():
+				finding.Status = roleMigratable
+				finding.Recommendation = capacity.RecommendedArch
+				finding.Message = "can migrate to " + capacity.RecommendedArch
+			default:
+				finding.Status = roleBlocked
+				finding.Message = capacity.reason()
+			}
+			findings = append(findings, finding)
+		}
+		if len(findings) == 0 {
+			markUnknown(&analysis, "capacity_providers", "No capacity providers attached")
+			continue
+		}
+		if fargateOnly {
+			analysis.setArchitecture(ArchitectureManaged)
+			analysis.decide(StatusNotApplicable, FindingNotApplicable, "capacity_providers", "Fargate capacity providers take their architecture from each task definition")
+			continue
+		}
+		applyRoleFindings(&analysis, findings, "capacity providers")
+	}
+	return analysis
+}
+
+// analyzeCapacityProvider analyzes the named EC2 capacity provider through
+// its Auto Scaling group.
+func (c *Context) analyzeCapacityProvider(name string) (ARM64Analysis, bool) {
+	for _, provider := range c.FindResources("aws_ecs_capacity_provider") {
+		for _, instance := range provider.Instances {
+			if providerName, _ := instance.Attributes["name"].(string); providerName != name {
+				continue
+			}
+			provider.Instances = []parser.ResourceInstance{instance}
+			return (&CapacityProviderAnalyzer{ctx: c}).Analyze(provider), true
+		}
+	}
+	return ARM64Analysis{}, false
+}
+
+// findAutoScalingGroup finds the aws_autoscaling_group instance with the
+// given ARN or name.
+func (c *Context) findAutoScalingGroup(reference string) (parser.TerraformResource, bool) {
+	if reference == "" {
+		return parser.TerraformResource{}, false
+	}
+	for _, group := range c.FindResources("aws_autoscaling_group") {
+		for _, instance := range group.Instances {
+			arn, _ := instance.Attributes["arn"].(string)
+			name, _ := instance.Attributes["name"].(string)
+			if arn != reference && name != reference {
+				continue
+			}
+			group.Instances = []parser.ResourceInstance{instance}
+			return group, true
+		}
+	}
+	return parser.TerraformResource{}, false
+}
+
+func isFargateCapacityProvider(name string) bool {
+	return name == "FARGATE" || name == "FARGATE_SPOT"
+}
+
+// getECSClusterName returns the name of a cluster from its name or ARN, e.g.
+// "arn:aws:ecs:us-east-1:123456789012:cluster/main" -> "main".
+func getECSClusterName(cluster string) string {
+	if _, name, found := strings.Cut(cluster, ":cluster/"); found {
+		return name
+	}
+	return cluster
+}
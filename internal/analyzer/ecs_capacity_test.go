@@ -0,0 +1,182 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func newECSCapacityTestState() *parser.TerraformState {
+	resource := func(resourceType, name string, attributes map[string]interface{}) parser.TerraformResource {
+		return parser.TerraformResource{
+			Mode:      "managed",
+			Type:      resourceType,
+			Name:      name,
+			Instances: []parser.ResourceInstance{{Attributes: attributes}},
+		}
+	}
+	group := func(name, launchTemplate string) parser.TerraformResource {
+		return resource("aws_autoscaling_group", name, map[string]interface{}{
+			"arn":             "arn:aws:autoscaling:us-east-1:123456789012:autoScalingGroup:" + name,
+			"name":            name,
+			"launch_template": []any{map[string]any{"name": launchTemplate}},
+		})
+	}
+	provider := func(name string) parser.TerraformResource {
+		return resource("aws_ecs_capacity_provider", name, map[string]interface{}{
+			"name": name,
+			"auto_scaling_group_provider": []any{
+				map[string]any{"auto_scaling_group_arn": "arn:aws:autoscaling:us-east-1:123456789012:autoScalingGroup:" + name},
+			},
+		})
+	}
+	taskDefinition := func(family, cpuArchitecture string) parser.TerraformResource {
+		attributes := map[string]interface{}{
+			"arn":      "arn:aws:ecs:us-east-1:123456789012:task-definition/" + family + ":3",
+			"family":   family,
+			"revision": float64(3),
+		}
+		if cpuArchitecture != "" {
+			attributes["runtime_platform"] = []any{map[string]any{"cpu_architecture": cpuArchitecture}}
+		}
+		return resource("aws_ecs_task_definition", family, attributes)
+	}
+
+	return &parser.TerraformState{
+		Version: 4,
+		Resources: []parser.TerraformResource{
+			resource("aws_launch_template", "x86", map[string]interface{}{"name": "x86", "instance_type": "m5.large"}),
+			resource("aws_launch_template", "arm", map[string]interface{}{"name": "arm", "instance_type": "m7g.large"}),
+			group("x86", "x86"),
+			group("arm", "arm"),
+			provider("x86"),
+			provider("arm"),
+			resource("aws_ecs_cluster_capacity_providers", "main", map[string]interface{}{
+				"cluster_name":       "main",
+				"capacity_providers": []any{"x86", "FARGATE"},
+				"default_capacity_provider_strategy": []any{
+					map[string]any{"capacity_provider": "x86"},
+				},
+			}),
+			taskDefinition("api", ""),
+			taskDefinition("worker", "ARM64"),
+		},
+	}
+}
+
+func TestCapacityProviderAnalyzers_Analyze(t *testing.T) {
+	ctx := NewContext(newECSCapacityTestState())
+
+	provider := AnalyzeResourceWithContext(parser.TerraformResource{
+		Type: "aws_ecs_capacity_provider",
+		Name: "x86",
+		Instances: []parser.ResourceInstance{
+			{
+				Attributes: map[string]interface{}{
+					"name": "x86",
+					"auto_scaling_group_provider": []any{
+						map[string]any{"auto_scaling_group_arn": "arn:aws:autoscaling:us-east-1:123456789012:autoScalingGroup:x86"},
+					},
+				},
+			},
+		},
+	}, ctx)
+	if !provider.ARM64Compatible || provider.RecommendedArch != "m7g.large" {
+		t.Errorf("capacity provider = %v %q, want compatible with m7g.large", provider.ARM64Compatible, provider.RecommendedArch)
+	}
+	if !strings.Contains(provider.Notes, "Backed by aws_autoscaling_group.x86") {
+		t.Errorf("capacity provider Notes = %q, want the backing Auto Scaling group", provider.Notes)
+	}
+
+	cluster := AnalyzeResourceWithContext(parser.TerraformResource{
+		Type: "aws_ecs_cluster_capacity_providers",
+		Name: "main",
+		Instances: []parser.ResourceInstance{
+			{Attributes: map[string]interface{}{"cluster_name": "main", "capacity_providers": []any{"arm", "x86", "FARGATE"}}},
+		},
+	}, ctx)
+	if !strings.Contains(cluster.Notes, "Partially migrated: 1 of 2 capacity providers use ARM64") {
+		t.Errorf("cluster capacity providers Notes = %q, want partial migration", cluster.Notes)
+	}
+}
+
+func TestECSServiceAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name        string
+		attributes  map[string]interface{}
+		expectARM64 bool
+		expectUsing bool
+		expectNotes string
+	}{
+		{
+			name: "EC2 launch type on x86 capacity",
+			attributes: map[string]interface{}{
+				"cluster":         "arn:aws:ecs:us-east-1:123456789012:cluster/main",
+				"launch_type":     "EC2",
+				"task_definition": "api:3",
+			},
+			expectARM64: true,
+			expectNotes: "Can migrate capacity providers to Graviton together with cpu_architecture = \"ARM64\" | EC2 capacity: x86 (X86_64) | aws_ecs_task_definition.api cpu_architecture is X86_64",
+		},
+		{
+			name: "Graviton capacity provider strategy",
+			attributes: map[string]interface{}{
+				"capacity_provider_strategy": []any{map[string]any{"capacity_provider": "arm"}},
+				"task_definition":            "arn:aws:ecs:us-east-1:123456789012:task-definition/worker:3",
+			},
+			expectARM64: true,
+			expectUsing: true,
+			expectNotes: "Already using Graviton capacity",
+		},
+		{
+			name: "task architecture disagrees with capacity",
+			attributes: map[string]interface{}{
+				"capacity_provider_strategy": []any{map[string]any{"capacity_provider": "arm"}},
+				"task_definition":            "api",
+			},
+			expectARM64: false,
+			expectNotes: "Misconfigured: aws_ecs_task_definition.api cpu_architecture is X86_64 but its capacity is ARM64",
+		},
+		{
+			name: "cluster default strategy",
+			attributes: map[string]interface{}{
+				"cluster":         "main",
+				"task_definition": "api:3",
+			},
+			expectARM64: true,
+			expectNotes: "EC2 capacity: x86 (X86_64)",
+		},
+		{
+			name: "Fargate service",
+			attributes: map[string]interface{}{
+				"launch_type":     "FARGATE",
+				"task_definition": "worker:3",
+			},
+			expectARM64: true,
+			expectUsing: true,
+			expectNotes: "Already using ARM64 on Fargate",
+		},
+	}
+
+	ctx := NewContext(newECSCapacityTestState())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Type:      "aws_ecs_service",
+				Name:      "service",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			}, ctx)
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/imagecache"
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestECSAnalyzer_ContainerImages(t *testing.T) {
+	ctx := NewContext(&parser.TerraformState{Version: 4})
+	ctx.Images = newTestImageCache(t, map[string][]string{
+		"nginx:1.25":                          {"amd64", "arm64"},
+		"registry.example.com/legacy-app:2.0": {"amd64"},
+	})
+	arm64Platform := []any{map[string]any{"cpu_architecture": "ARM64", "operating_system_family": "LINUX"}}
+
+	tests := []struct {
+		name          string
+		attributes    map[string]any
+		expectStatus  Status
+		expectCodes   []FindingCode
+		expectMessage string
+	}{
+		{
+			name: "multi-arch container",
+			attributes: map[string]any{
+				"container_definitions": `[{"name":"web","image":"nginx:1.25"}]`,
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote},
+			expectMessage: "Containers: web (nginx:1.25): arm64 available",
+		},
+		{
+			name: "x86-only container blocks the migration",
+			attributes: map[string]any{
+				"container_definitions": `[{"name":"web","image":"nginx:1.25"},{"name":"app","image":"registry.example.com/legacy-app:2.0"}]`,
+			},
+			expectStatus:  StatusBlocked,
+			expectCodes:   []FindingCode{FindingBlocked, FindingNote},
+			expectMessage: "containers without an arm64 image: app",
+		},
+		{
+			name: "ARM64 task with an x86-only container",
+			attributes: map[string]any{
+				"runtime_platform":      arm64Platform,
+				"container_definitions": `[{"name":"app","image":"registry.example.com/legacy-app:2.0"}]`,
+			},
+			expectStatus:  StatusMisconfigured,
+			expectCodes:   []FindingCode{FindingMisconfigured, FindingNote},
+			expectMessage: "Using ARM64 but containers lack an arm64 image: app",
+		},
+		{
+			name: "ARM64 task with a multi-arch container",
+			attributes: map[string]any{
+				"runtime_platform":      arm64Platform,
+				"container_definitions": `[{"name":"web","image":"nginx:1.25"}]`,
+			},
+			expectStatus: StatusAlreadyARM64,
+			expectCodes:  []FindingCode{FindingAlreadyARM64, FindingNote},
+		},
+		{
+			name: "container missing from the image cache",
+			attributes: map[string]any{
+				"container_definitions": `[{"name":"sidecar","image":"registry.example.com/sidecar:1.0"}]`,
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingUnverified},
+			expectMessage: "Containers: sidecar (registry.example.com/sidecar:1.0): not in image cache",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Mode:      "managed",
+				Type:      "aws_ecs_task_definition",
+				Name:      "task",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			}, ctx)
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v (notes: %s)", analysis.Status, tt.expectStatus, analysis.Notes)
+			}
+			if codes := getFindingCodes(analysis); !slices.Equal(codes, tt.expectCodes) {
+				t.Errorf("finding codes = %v, want %v", codes, tt.expectCodes)
+			}
+			if tt.expectMessage != "" && !slices.ContainsFunc(analysis.Findings, func(finding Finding) bool { return finding.Message == tt.expectMessage }) {
+				t.Errorf("Findings = %+v, want message %q", analysis.Findings, tt.expectMessage)
+			}
+		})
+	}
+}
+
+// newTestImageCache opens an image cache holding a manifest list for each
+// reference, published for the given architectures.
+func newTestImageCache(t *testing.T, images map[string][]string) *imagecache.Cache {
+	t.Helper()
+	dir := t.TempDir()
+	for ref, architectures := range images {
+		separator := strings.LastIndex(ref, ":")
+		var manifests []map[string]any
+		for _, architecture := range architectures {
+			manifests = append(manifests, map[string]any{"platform": map[string]string{"architecture": architecture, "os": "linux"}})
+		}
+		data, err := json.Marshal(map[string]any{"manifests": manifests})
+		if err != nil {
+			t.Fatalf("Failed to encode manifest list: %v", err)
+		}
+		path := filepath.Join(dir, "manifests", ref[:separator], ref[separator+1:]+".json")
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+
+	cache, err := imagecache.Open(dir)
+	if err != nil {
+		t.Fatalf("imagecache.Open() error = %v", err)
+	}
+	return cache
+}
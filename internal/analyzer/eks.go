@@ -1,6 +1,11 @@
 package analyzer
 
-import "github.com/suer/tf-arm/internal/parser"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
 
 type EKSAnalyzer struct{}
 
@@ -12,101 +17,124 @@ func (a *EKSAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
 	analysis := ARM64Analysis{
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
-		ARM64Compatible: true,
-		CurrentArch:     "X86_64",
+		ARM64Compatible: false,
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
-		if instanceTypes, exists := instance.Attributes["instance_types"]; exists {
-			instanceTypesList, ok := instanceTypes.([]any)
-			if !ok {
-				continue
-			}
-			if len(instanceTypesList) > 0 {
-				instanceType, ok := instanceTypesList[0].(string)
-				if !ok {
-					continue
-				}
+		amiType := "AL2_x86_64"
+		if amiTypeStr, ok := instance.Attributes["ami_type"].(string); ok && amiTypeStr != "" {
+			amiType = amiTypeStr
+		}
+		if strings.HasPrefix(amiType, "WINDOWS_") {
+			markNotApplicableWindows(&analysis, amiType)
+			continue
+		}
 
-				if isARM64InstanceType(instanceType) {
-					analysis.CurrentArch = "ARM64"
-					analysis.RecommendedArch = "ARM64"
-					analysis.AlreadyUsingARM64 = true
-					analysis.Notes = "Already using ARM64 instance type"
-				} else if hasARM64Alternative(instanceType) {
-					analysis.RecommendedArch = getARM64Alternative(instanceType)
-					analysis.Notes = "Can migrate to ARM64 instance type: " + analysis.RecommendedArch
-				} else {
-					analysis.Notes = "Can use ARM64 instance types for EKS node group"
+		var instanceTypes []string
+		if instanceTypesList, ok := instance.Attributes["instance_types"].([]any); ok {
+			for _, instanceType := range instanceTypesList {
+				if instanceTypeStr, ok := instanceType.(string); ok {
+					instanceTypes = append(instanceTypes, instanceTypeStr)
 				}
 			}
-		} else {
-			analysis.Notes = "Can specify ARM64 instance types for EKS node group"
 		}
 
-		// Check AMI type
-		if amiType, exists := instance.Attributes["ami_type"]; exists {
-			amiTypeStr, ok := amiType.(string)
-			if !ok {
-				continue
-			}
-			if amiTypeStr == "AL2_ARM_64" {
-				analysis.CurrentArch = "ARM64"
-				analysis.AlreadyUsingARM64 = true
-				analysis.Notes = "Already using ARM64 AMI type"
+		var armTypes, x86Types []string
+		for _, instanceType := range instanceTypes {
+			if isARM64InstanceType(instanceType) {
+				armTypes = append(armTypes, instanceType)
 			} else {
-				analysis.Notes += " | Consider using AL2_ARM_64 AMI type"
+				x86Types = append(x86Types, instanceType)
 			}
 		}
+
+		armAMI := isARM64EKSAMIType(amiType)
+		switch {
+		case len(armTypes) > 0 && len(x86Types) > 0:
+			analysis.setArchitecture(ArchitectureMixed)
+			analysis.RecommendedArch = ""
+			analysis.decide(StatusMisconfigured, FindingMisconfigured, "instance_types", fmt.Sprintf("instance_types mixes arm64 (%s) and x86_64 (%s) with a single ami_type %s",
+				strings.Join(armTypes, ", "), strings.Join(x86Types, ", "), amiType))
+		case armAMI && len(x86Types) > 0:
+			analysis.setArchitecture(ArchitectureMixed)
+			analysis.RecommendedArch = ""
+			analysis.decide(StatusMisconfigured, FindingMisconfigured, "ami_type", "ARM64 ami_type "+amiType+" with x86_64 instance types "+strings.Join(x86Types, ", "))
+		case !armAMI && len(armTypes) > 0:
+			analysis.setArchitecture(ArchitectureMixed)
+			analysis.RecommendedArch = ""
+			analysis.decide(StatusMisconfigured, FindingMisconfigured, "ami_type", "x86_64 ami_type "+amiType+" with arm64 instance types "+strings.Join(armTypes, ", "))
+		case armAMI:
+			analysis.setArchitecture(ArchitectureARM64)
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "ami_type", "Already using ARM64 AMI type "+amiType)
+		default:
+			a.recommend(&analysis, amiType, x86Types)
+		}
 	}
 	return analysis
 }
 
-type FargateAnalyzer struct{}
-
-func (a *FargateAnalyzer) SupportedType() string {
-	return "aws_ecs_service"
-}
+// recommend fills in the Graviton instance types and ARM AMI type for an
+// x86_64 node group, keeping the node group's OS family.
+func (a *EKSAnalyzer) recommend(analysis *ARM64Analysis, amiType string, instanceTypes []string) {
+	armAMIType, hasARMAMI := getEKSAMITypeX86ToArm64Map()[amiType]
+	if !hasARMAMI {
+		analysis.RecommendedArch = ""
+		if amiType == "CUSTOM" {
+			analysis.decide(StatusBlocked, FindingBlocked, "ami_type", "Custom AMI: build an arm64 AMI for the launch template before migrating")
+		} else {
+			analysis.decide(StatusBlocked, FindingNoARM64Option, "ami_type", "No ARM64 AMI type available for "+amiType)
+		}
+		return
+	}
 
-func (a *FargateAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
-	analysis := ARM64Analysis{
-		ResourceType:    resource.Type,
-		ResourceName:    resource.Name,
-		ARM64Compatible: true,
-		CurrentArch:     "X86_64 (default)",
-		RecommendedArch: "ARM64",
+	if len(instanceTypes) == 0 {
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusMigratable, FindingMigratable, "instance_types", "Can specify ARM64 instance types for EKS node group with ami_type "+armAMIType)
+		return
 	}
 
-	for _, instance := range resource.Instances {
-		if launchType, exists := instance.Attributes["launch_type"]; exists {
-			if launchType == "FARGATE" {
-				analysis.Notes = "Fargate supports ARM64. Check task definition cpu_architecture"
-			} else {
-				analysis.Notes = "Not using Fargate launch type"
-				analysis.ARM64Compatible = false
-			}
+	var recommended, migrations, missing []string
+	for _, instanceType := range instanceTypes {
+		if hasARM64Alternative(instanceType) {
+			armType := getARM64Alternative(instanceType)
+			recommended = append(recommended, armType)
+			migrations = append(migrations, instanceType+" -> "+armType)
 		} else {
-			// Check if using capacity provider strategy for Fargate
-			if capacityProviderStrategy, exists := instance.Attributes["capacity_provider_strategy"]; exists {
-				strategies, ok := capacityProviderStrategy.([]any)
-				if !ok {
-					continue
-				}
-				for _, strategy := range strategies {
-					strategyMap, ok := strategy.(map[string]any)
-					if !ok {
-						continue
-					}
-					if provider, exists := strategyMap["capacity_provider"]; exists {
-						if provider == "FARGATE" || provider == "FARGATE_SPOT" {
-							analysis.Notes = "Using Fargate capacity provider. Check task definition cpu_architecture"
-							return analysis
-						}
-					}
-				}
-			}
-			analysis.Notes = "Service configuration unclear for ARM64 compatibility"
+			missing = append(missing, instanceType)
 		}
 	}
-	return analysis
+
+	if len(recommended) == 0 {
+		analysis.RecommendedArch = ""
+		analysis.decide(StatusBlocked, FindingNoARM64Option, "instance_types", "No ARM64 compatible instance type available for "+strings.Join(missing, ", "))
+		return
+	}
+
+	analysis.RecommendedArch = strings.Join(recommended, ", ")
+	analysis.decide(StatusMigratable, FindingMigratable, "instance_types", "Can migrate to ARM64 instance types: "+strings.Join(migrations, ", "))
+	analysis.addFinding(FindingNote, "ami_type", "Use ami_type "+armAMIType)
+	if len(missing) > 0 {
+		analysis.addFinding(FindingNoARM64Option, "instance_types", "No direct ARM64 alternative for "+strings.Join(missing, ", "))
+	}
+}
+
+func isARM64EKSAMIType(amiType string) bool {
+	return strings.Contains(amiType, "_ARM_64")
+}
+
+// getEKSAMITypeX86ToArm64Map maps x86_64 EKS AMI types to the ARM AMI type
+// of the same OS family.
+func getEKSAMITypeX86ToArm64Map() map[string]string {
+	return map[string]string{
+		// Amazon Linux 2
+		"AL2_x86_64": "AL2_ARM_64",
+		// Amazon Linux 2023
+		"AL2023_x86_64_STANDARD": "AL2023_ARM_64_STANDARD",
+		"AL2023_x86_64_NVIDIA":   "AL2023_ARM_64_NVIDIA",
+		// Bottlerocket
+		"BOTTLEROCKET_x86_64":        "BOTTLEROCKET_ARM_64",
+		"BOTTLEROCKET_x86_64_NVIDIA": "BOTTLEROCKET_ARM_64_NVIDIA",
+	}
 }
@@ -0,0 +1,111 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestEKSAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name            string
+		attributes      map[string]interface{}
+		expectARM64     bool
+		expectUsing     bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name: "AL2023 ARM node group",
+			attributes: map[string]interface{}{
+				"ami_type":       "AL2023_ARM_64_STANDARD",
+				"instance_types": []any{"m7g.large", "c7g.large"},
+			},
+			expectARM64:     true,
+			expectUsing:     true,
+			expectRecommend: "ARM64",
+			expectNotes:     "Already using ARM64 AMI type AL2023_ARM_64_STANDARD",
+		},
+		{
+			name: "Bottlerocket x86 node group keeps OS family",
+			attributes: map[string]interface{}{
+				"ami_type":       "BOTTLEROCKET_x86_64",
+				"instance_types": []any{"m5.large", "c5.xlarge"},
+			},
+			expectARM64:     true,
+			expectRecommend: "m7g.large, c7g.xlarge",
+			expectNotes:     "Use ami_type BOTTLEROCKET_ARM_64",
+		},
+		{
+			name: "AL2023 x86 node group",
+			attributes: map[string]interface{}{
+				"ami_type":       "AL2023_x86_64_STANDARD",
+				"instance_types": []any{"t3.medium"},
+			},
+			expectARM64:     true,
+			expectRecommend: "t4g.medium",
+			expectNotes:     "Use ami_type AL2023_ARM_64_STANDARD",
+		},
+		{
+			name: "mixed instance types are misconfigured",
+			attributes: map[string]interface{}{
+				"ami_type":       "AL2_x86_64",
+				"instance_types": []any{"m5.large", "m7g.large"},
+			},
+			expectARM64: false,
+			expectNotes: "Misconfigured: instance_types mixes arm64 (m7g.large) and x86_64 (m5.large)",
+		},
+		{
+			name: "ARM AMI with x86 instance types is misconfigured",
+			attributes: map[string]interface{}{
+				"ami_type":       "BOTTLEROCKET_ARM_64",
+				"instance_types": []any{"m5.large"},
+			},
+			expectARM64: false,
+			expectNotes: "Misconfigured: ARM64 ami_type",
+		},
+		{
+			name: "instance types without ARM equivalents block the node group",
+			attributes: map[string]interface{}{
+				"ami_type":       "AL2_x86_64",
+				"instance_types": []any{"p3.2xlarge", "x1.16xlarge"},
+			},
+			expectARM64: false,
+			expectNotes: "No ARM64 compatible instance type available for p3.2xlarge, x1.16xlarge",
+		},
+		{
+			name: "GPU AMI has no ARM equivalent",
+			attributes: map[string]interface{}{
+				"ami_type":       "AL2_x86_64_GPU",
+				"instance_types": []any{"g4dn.xlarge"},
+			},
+			expectARM64: false,
+			expectNotes: "No ARM64 AMI type available for AL2_x86_64_GPU",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &EKSAnalyzer{}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type:      "aws_eks_node_group",
+				Name:      "nodes",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
@@ -1,8 +1,15 @@
 package analyzer
 
-import "github.com/suer/tf-arm/internal/parser"
+import (
+	"fmt"
+	"strings"
 
-type ElastiCacheAnalyzer struct{}
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type ElastiCacheAnalyzer struct {
+	ctx *Context
+}
 
 func (a *ElastiCacheAnalyzer) SupportedType() string {
 	return "aws_elasticache_cluster"
@@ -13,8 +20,8 @@ func (a *ElastiCacheAnalyzer) Analyze(resource parser.TerraformResource) ARM64An
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
 		ARM64Compatible: false,
-		CurrentArch:     "X86_64",
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
 		if nodeType, exists := instance.Attributes["node_type"]; exists {
@@ -23,24 +30,201 @@ func (a *ElastiCacheAnalyzer) Analyze(resource parser.TerraformResource) ARM64An
 				continue
 			}
 
-			if isARM64ElastiCacheNodeType(nodeTypeStr) {
-				analysis.ARM64Compatible = true
-				analysis.CurrentArch = "ARM64"
-				analysis.RecommendedArch = "ARM64"
-				analysis.AlreadyUsingARM64 = true
-				analysis.Notes = "Already using ARM64 node type"
-			} else if hasARM64ElastiCacheAlternative(nodeTypeStr) {
-				analysis.ARM64Compatible = true
-				analysis.RecommendedArch = getARM64ElastiCacheAlternative(nodeTypeStr)
-				analysis.Notes = "Can migrate to ARM64 node type: " + analysis.RecommendedArch
-			} else {
-				analysis.Notes = "No ARM64 compatible node type available"
+			applyElastiCacheNodeType(&analysis, "node_type", nodeTypeStr)
+			if analysis.canMigrate() {
+				engine, _ := instance.Attributes["engine"].(string)
+				engineVersion, _, _ := a.ctx.lookupAttribute(resource, instance.Attributes, "engine_version")
+				engineVersionStr, _ := engineVersion.(string)
+				applyElastiCacheEngineEligibility(&analysis, engine, engineVersionStr)
+			}
+		}
+	}
+	return analysis
+}
+
+type ElastiCacheReplicationGroupAnalyzer struct {
+	ctx *Context
+}
+
+func (a *ElastiCacheReplicationGroupAnalyzer) SupportedType() string {
+	return "aws_elasticache_replication_group"
+}
+
+func (a *ElastiCacheReplicationGroupAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		nodeType, ok := instance.Attributes["node_type"].(string)
+		if !ok || nodeType == "" {
+			continue
+		}
+
+		applyElastiCacheNodeType(&analysis, "node_type", nodeType)
+		if analysis.canMigrate() {
+			engine, _ := instance.Attributes["engine"].(string)
+			engineVersion, _, _ := a.ctx.lookupAttribute(resource, instance.Attributes, "engine_version")
+			engineVersionStr, _ := engineVersion.(string)
+			applyElastiCacheEngineEligibility(&analysis, engine, engineVersionStr)
+		}
+
+		if numCacheClusters, _, exists := a.ctx.lookupAttribute(resource, instance.Attributes, "num_cache_clusters"); exists {
+			if count, ok := numCacheClusters.(float64); ok && count > 0 {
+				analysis.addFinding(FindingNote, "num_cache_clusters", fmt.Sprintf("%d cache nodes", int(count)))
 			}
 		}
+
+		// Members of a global datastore change node type through the datastore
+		if globalID, ok := instance.Attributes["global_replication_group_id"].(string); ok && globalID != "" {
+			analysis.addFinding(FindingNote, "global_replication_group_id", "Member of global datastore "+globalID+"; change cache_node_type on the global datastore")
+		}
 	}
 	return analysis
 }
 
+type ElastiCacheGlobalDatastoreAnalyzer struct {
+	ctx *Context
+}
+
+func (a *ElastiCacheGlobalDatastoreAnalyzer) SupportedType() string {
+	return "aws_elasticache_global_replication_group"
+}
+
+func (a *ElastiCacheGlobalDatastoreAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		nodeType, ok := instance.Attributes["cache_node_type"].(string)
+		if !ok || nodeType == "" {
+			analysis.setArchitecture(ArchitectureUnknown)
+			markUnknown(&analysis, "cache_node_type", "cache_node_type not in state; node type follows the primary replication group")
+			continue
+		}
+
+		applyElastiCacheNodeType(&analysis, "cache_node_type", nodeType)
+		if analysis.canMigrate() {
+			engine, _ := instance.Attributes["engine"].(string)
+			engineVersion, _, _ := a.ctx.lookupAttribute(resource, instance.Attributes, "engine_version")
+			engineVersionStr, _ := engineVersion.(string)
+			applyElastiCacheEngineEligibility(&analysis, engine, engineVersionStr)
+		}
+
+		globalID, _ := instance.Attributes["global_replication_group_id"].(string)
+		if members := a.countMembers(globalID); members > 0 {
+			analysis.addFinding(FindingNote, "", fmt.Sprintf("Applies to %d member replication groups in state", members))
+		}
+	}
+	return analysis
+}
+
+// countMembers returns how many replication groups in the state belong to the
+// global datastore.
+func (a *ElastiCacheGlobalDatastoreAnalyzer) countMembers(globalID string) int {
+	if globalID == "" {
+		return 0
+	}
+
+	var count int
+	for _, group := range a.ctx.FindResources("aws_elasticache_replication_group") {
+		for _, instance := range group.Instances {
+			if id, _ := instance.Attributes["global_replication_group_id"].(string); id == globalID {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+type ElastiCacheServerlessAnalyzer struct{}
+
+func (a *ElastiCacheServerlessAnalyzer) SupportedType() string {
+	return "aws_elasticache_serverless_cache"
+}
+
+func (a *ElastiCacheServerlessAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType: resource.Type,
+		ResourceName: resource.Name,
+	}
+	analysis.setArchitecture(ArchitectureManaged)
+	analysis.decide(StatusNotApplicable, FindingNotApplicable, "", "ElastiCache Serverless manages its own capacity")
+	return analysis
+}
+
+// applyElastiCacheNodeType records the Graviton decision for a cache node type.
+func applyElastiCacheNodeType(analysis *ARM64Analysis, attribute, nodeType string) {
+	if isARM64ElastiCacheNodeType(nodeType) {
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, attribute, "Already using ARM64 node type")
+	} else if hasARM64ElastiCacheAlternative(nodeType) {
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.RecommendedArch = getARM64ElastiCacheAlternative(nodeType)
+		analysis.decide(StatusMigratable, FindingMigratable, attribute, "Can migrate to ARM64 node type: "+analysis.RecommendedArch)
+	} else {
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.decide(StatusBlocked, FindingNoARM64Option, attribute, "No ARM64 compatible node type available")
+	}
+}
+
+// applyElastiCacheEngineEligibility adds an engine upgrade as a prerequisite
+// when the engine version is below the minimum for the recommended node type.
+func applyElastiCacheEngineEligibility(analysis *ARM64Analysis, engine, engineVersion string) {
+	if engine == "" {
+		engine = "redis"
+	}
+	minimums := getElastiCacheEngineEligibility()[engine][getGravitonGeneration(analysis.RecommendedArch)]
+	if len(minimums) == 0 {
+		return
+	}
+
+	if engineVersion == "" {
+		analysis.addFinding(FindingMissingAttribute, "engine_version", "engine_version not in state; "+engine+" needs "+minimums[0]+" or later on "+analysis.RecommendedArch)
+		return
+	}
+	if upgradeTo := getRequiredElastiCacheUpgrade(engineVersion, minimums); upgradeTo != "" {
+		analysis.addFinding(FindingPrerequisite, "engine_version", fmt.Sprintf("upgrade engine_version %s -> %s", engineVersion, upgradeTo))
+	}
+}
+
+// getRequiredElastiCacheUpgrade is getRequiredEngineUpgrade for ElastiCache
+// versions, which may be given as "6.x" to track the newest release of a major
+// version; such versions meet every minimum on their major version.
+func getRequiredElastiCacheUpgrade(version string, minimums []string) string {
+	major, wildcard := strings.CutSuffix(version, ".x")
+	if !wildcard {
+		return getRequiredEngineUpgrade(version, minimums)
+	}
+	if minimumMajor, _, _ := strings.Cut(minimums[0], "."); compareVersions(major, minimumMajor) < 0 {
+		return minimums[0]
+	}
+	return ""
+}
+
+// getElastiCacheEngineEligibility lists, per engine and Graviton generation,
+// the minimum engine version needed for Graviton node types.
+func getElastiCacheEngineEligibility() map[string]map[int][]string {
+	return map[string]map[int][]string{
+		"redis": {
+			2: {"5.0.6"},
+			3: {"6.2"},
+		},
+		"memcached": {
+			2: {"1.5.16"},
+			3: {"1.5.16"},
+		},
+	}
+}
+
 type MemoryDBAnalyzer struct{}
 
 func (a *MemoryDBAnalyzer) SupportedType() string {
@@ -52,8 +236,8 @@ func (a *MemoryDBAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analy
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
 		ARM64Compatible: false,
-		CurrentArch:     "X86_64",
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
 		if nodeType, exists := instance.Attributes["node_type"]; exists {
@@ -63,17 +247,16 @@ func (a *MemoryDBAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analy
 			}
 
 			if isARM64MemoryDBNodeType(nodeTypeStr) {
-				analysis.ARM64Compatible = true
-				analysis.CurrentArch = "ARM64"
+				analysis.setArchitecture(ArchitectureARM64)
 				analysis.RecommendedArch = "ARM64"
-				analysis.AlreadyUsingARM64 = true
-				analysis.Notes = "Already using ARM64 node type"
+				analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "node_type", "Already using ARM64 node type")
 			} else if hasARM64MemoryDBAlternative(nodeTypeStr) {
-				analysis.ARM64Compatible = true
+				analysis.setArchitecture(ArchitectureX86_64)
 				analysis.RecommendedArch = getARM64MemoryDBAlternative(nodeTypeStr)
-				analysis.Notes = "Can migrate to ARM64 node type: " + analysis.RecommendedArch
+				analysis.decide(StatusMigratable, FindingMigratable, "node_type", "Can migrate to ARM64 node type: "+analysis.RecommendedArch)
 			} else {
-				analysis.Notes = "No ARM64 compatible node type available"
+				analysis.setArchitecture(ArchitectureX86_64)
+				analysis.decide(StatusBlocked, FindingNoARM64Option, "node_type", "No ARM64 compatible node type available")
 			}
 		}
 	}
@@ -88,6 +271,8 @@ func isARM64ElastiCacheNodeType(nodeType string) bool {
 		"cache.r6gd.large", "cache.r6gd.xlarge", "cache.r6gd.2xlarge", "cache.r6gd.4xlarge",
 		"cache.r6gd.8xlarge", "cache.r6gd.12xlarge", "cache.r6gd.16xlarge",
 		"cache.t4g.nano", "cache.t4g.micro", "cache.t4g.small", "cache.t4g.medium",
+		"cache.m6g.large", "cache.m6g.xlarge", "cache.m6g.2xlarge", "cache.m6g.4xlarge",
+		"cache.m6g.8xlarge", "cache.m6g.12xlarge", "cache.m6g.16xlarge",
 		// Graviton3
 		"cache.m7g.large", "cache.m7g.xlarge", "cache.m7g.2xlarge", "cache.m7g.4xlarge",
 		"cache.m7g.8xlarge", "cache.m7g.12xlarge", "cache.m7g.16xlarge",
@@ -0,0 +1,258 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestGetRequiredElastiCacheUpgrade(t *testing.T) {
+	tests := []struct {
+		version  string
+		minimums []string
+		expected string
+	}{
+		{"6.x", []string{"6.2"}, ""},
+		{"7.x", []string{"6.2"}, ""},
+		{"5.0.6", []string{"6.2"}, "6.2"},
+		{"6.0.5", []string{"6.2"}, "6.2"},
+		{"6.2.6", []string{"6.2"}, ""},
+		{"7.1", []string{"6.2"}, ""},
+		{"4.0.10", []string{"5.0.6"}, "5.0.6"},
+		{"5.0.0", []string{"5.0.6"}, "5.0.6"},
+		{"6.x", []string{"5.0.6"}, ""},
+		{"1.5.10", []string{"1.5.16"}, "1.5.16"},
+		{"1.6.17", []string{"1.5.16"}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := getRequiredElastiCacheUpgrade(tt.version, tt.minimums); got != tt.expected {
+			t.Errorf("getRequiredElastiCacheUpgrade(%q, %v) = %q, want %q", tt.version, tt.minimums, got, tt.expected)
+		}
+	}
+}
+
+func TestElastiCacheAnalyzers_Analyze(t *testing.T) {
+	const awsProvider = `provider["registry.terraform.io/hashicorp/aws"]`
+	members := []parser.TerraformResource{
+		{
+			Mode: "managed",
+			Type: "aws_elasticache_replication_group",
+			Name: "primary",
+			Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+				"global_replication_group_id": "ldgnf-sessions",
+			}}},
+		},
+		{
+			Mode: "managed",
+			Type: "aws_elasticache_replication_group",
+			Name: "secondary",
+			Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+				"global_replication_group_id": "ldgnf-sessions",
+			}}},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		resourceType  string
+		attributes    map[string]any
+		providerMajor int
+		expectStatus  Status
+		expectArch    Architecture
+		expectCodes   []FindingCode
+		expectMessage string
+	}{
+		{
+			name:         "Redis cluster on a Graviton3 capable version",
+			resourceType: "aws_elasticache_cluster",
+			attributes:   map[string]any{"engine": "redis", "engine_version": "7.1", "node_type": "cache.m5.large"},
+			expectStatus: StatusMigratable,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingMigratable},
+		},
+		{
+			name:          "Redis cluster below the Graviton3 minimum",
+			resourceType:  "aws_elasticache_cluster",
+			attributes:    map[string]any{"engine": "redis", "engine_version": "6.0.5", "node_type": "cache.r5.large"},
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingPrerequisite},
+			expectMessage: "upgrade engine_version 6.0.5 -> 6.2",
+		},
+		{
+			name:         "Redis 6.x tracks the newest Redis 6 release",
+			resourceType: "aws_elasticache_cluster",
+			attributes:   map[string]any{"engine": "redis", "engine_version": "6.x", "node_type": "cache.m5.large"},
+			expectStatus: StatusMigratable,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingMigratable},
+		},
+		{
+			name:         "engine_version_actual takes precedence",
+			resourceType: "aws_elasticache_cluster",
+			attributes: map[string]any{
+				"engine":                "redis",
+				"engine_version":        "6.x",
+				"engine_version_actual": "6.0.5",
+				"node_type":             "cache.m5.large",
+			},
+			providerMajor: 3,
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingPrerequisite},
+			expectMessage: "upgrade engine_version 6.0.5 -> 6.2",
+		},
+		{
+			name:          "Memcached below the Graviton minimum",
+			resourceType:  "aws_elasticache_cluster",
+			attributes:    map[string]any{"engine": "memcached", "engine_version": "1.5.10", "node_type": "cache.t3.micro"},
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingPrerequisite},
+			expectMessage: "upgrade engine_version 1.5.10 -> 1.5.16",
+		},
+		{
+			name:         "engine version not in state",
+			resourceType: "aws_elasticache_cluster",
+			attributes:   map[string]any{"engine": "redis", "node_type": "cache.t3.small"},
+			expectStatus: StatusMigratable,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingMigratable, FindingMissingAttribute},
+		},
+		{
+			name:         "Graviton node type",
+			resourceType: "aws_elasticache_cluster",
+			attributes:   map[string]any{"engine": "redis", "engine_version": "7.1", "node_type": "cache.r7g.large"},
+			expectStatus: StatusAlreadyARM64,
+			expectArch:   ArchitectureARM64,
+			expectCodes:  []FindingCode{FindingAlreadyARM64},
+		},
+		{
+			name:         "node type without a Graviton option",
+			resourceType: "aws_elasticache_cluster",
+			attributes:   map[string]any{"engine": "redis", "engine_version": "7.1", "node_type": "cache.r4.large"},
+			expectStatus: StatusBlocked,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingNoARM64Option},
+		},
+		{
+			name:         "replication group on AWS provider v5",
+			resourceType: "aws_elasticache_replication_group",
+			attributes: map[string]any{
+				"engine":                "redis",
+				"engine_version":        "7.1",
+				"engine_version_actual": "7.1.0",
+				"node_type":             "cache.m5.large",
+				"num_cache_clusters":    float64(3),
+			},
+			providerMajor: 5,
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote},
+			expectMessage: "3 cache nodes",
+		},
+		{
+			name:         "replication group with the deprecated attribute on AWS provider v4",
+			resourceType: "aws_elasticache_replication_group",
+			attributes: map[string]any{
+				"engine":                "redis",
+				"engine_version":        "6.2",
+				"node_type":             "cache.m5.large",
+				"num_cache_clusters":    nil,
+				"number_cache_clusters": float64(2),
+			},
+			providerMajor: 4,
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote},
+			expectMessage: "2 cache nodes",
+		},
+		{
+			name:         "replication group in a global datastore",
+			resourceType: "aws_elasticache_replication_group",
+			attributes: map[string]any{
+				"engine":                      "redis",
+				"engine_version":              "7.1",
+				"node_type":                   "cache.m5.large",
+				"global_replication_group_id": "ldgnf-sessions",
+			},
+			expectStatus: StatusMigratable,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingMigratable, FindingNote},
+		},
+		{
+			name:         "global datastore",
+			resourceType: "aws_elasticache_global_replication_group",
+			attributes: map[string]any{
+				"global_replication_group_id": "ldgnf-sessions",
+				"cache_node_type":             "cache.r5.large",
+				"engine":                      "redis",
+				"engine_version_actual":       "7.1.0",
+			},
+			providerMajor: 5,
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote},
+			expectMessage: "Applies to 2 member replication groups in state",
+		},
+		{
+			name:         "global datastore on AWS provider v3",
+			resourceType: "aws_elasticache_global_replication_group",
+			attributes: map[string]any{
+				"global_replication_group_id": "ldgnf-sessions",
+				"cache_node_type":             "cache.r5.large",
+				"engine":                      "redis",
+				"engine_version_actual":       "6.2.6",
+			},
+			providerMajor: 3,
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote},
+		},
+		{
+			name:         "global datastore without a node type",
+			resourceType: "aws_elasticache_global_replication_group",
+			attributes:   map[string]any{"global_replication_group_id": "ldgnf-sessions"},
+			expectStatus: StatusUnknown,
+			expectArch:   ArchitectureUnknown,
+			expectCodes:  []FindingCode{FindingMissingAttribute},
+		},
+		{
+			name:         "serverless cache",
+			resourceType: "aws_elasticache_serverless_cache",
+			attributes:   map[string]any{"engine": "valkey"},
+			expectStatus: StatusNotApplicable,
+			expectArch:   ArchitectureManaged,
+			expectCodes:  []FindingCode{FindingNotApplicable},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewContext(&parser.TerraformState{Version: 4, Resources: members})
+			ctx.ProviderVersions = map[string]int{"aws": tt.providerMajor}
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Mode:      "managed",
+				Type:      tt.resourceType,
+				Name:      "cache",
+				Provider:  awsProvider,
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			}, ctx)
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v (notes: %s)", analysis.Status, tt.expectStatus, analysis.Notes)
+			}
+			if analysis.Architecture != tt.expectArch {
+				t.Errorf("Architecture = %v, want %v", analysis.Architecture, tt.expectArch)
+			}
+			if codes := getFindingCodes(analysis); !slices.Equal(codes, tt.expectCodes) {
+				t.Errorf("finding codes = %v, want %v", codes, tt.expectCodes)
+			}
+			if tt.expectMessage != "" && !slices.ContainsFunc(analysis.Findings, func(finding Finding) bool { return finding.Message == tt.expectMessage }) {
+				t.Errorf("Findings = %+v, want message %q", analysis.Findings, tt.expectMessage)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package analyzer
 
-import "github.com/suer/tf-arm/internal/parser"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
 
 type EMRAnalyzer struct{}
 
@@ -13,46 +18,258 @@ func (a *EMRAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
 		ARM64Compatible: false,
-		CurrentArch:     "X86_64",
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
-		if instanceGroups, exists := instance.Attributes["master_instance_group"]; exists {
-			instanceGroupList, ok := instanceGroups.([]any)
-			if !ok {
-				continue
+		releaseLabel, _ := instance.Attributes["release_label"].(string)
+		graviton3 := supportsEMRGraviton3(releaseLabel)
+
+		var findings []roleFinding
+		for _, role := range []string{"master", "core"} {
+			if instanceType := getEMRInstanceGroupType(instance.Attributes[role+"_instance_group"]); instanceType != "" {
+				findings = append(findings, getEMRNodeGroupFinding(role, role+"_instance_group.0.instance_type", []string{instanceType}, graviton3))
 			}
-			if len(instanceGroupList) > 0 {
-				instanceGroup, ok := instanceGroupList[0].(map[string]any)
-				if !ok {
-					continue
-				}
-				if instanceType, exists := instanceGroup["instance_type"]; exists {
-					instanceTypeStr, ok := instanceType.(string)
-					if !ok {
-						continue
-					}
-
-					if isARM64EMRInstanceType(instanceTypeStr) {
-						analysis.ARM64Compatible = true
-						analysis.CurrentArch = "ARM64"
-						analysis.RecommendedArch = "ARM64"
-						analysis.AlreadyUsingARM64 = true
-						analysis.Notes = "Already using ARM64 instance type for master node"
-					} else if hasARM64EMRAlternative(instanceTypeStr) {
-						analysis.ARM64Compatible = true
-						analysis.RecommendedArch = getARM64EMRAlternative(instanceTypeStr)
-						analysis.Notes = "Can migrate to ARM64 instance type: " + analysis.RecommendedArch
-					} else {
-						analysis.Notes = "EMR supports ARM64 with Graviton2 instances"
-					}
-				}
+			if instanceTypes := getEMRFleetInstanceTypes(instance.Attributes[role+"_instance_fleet"]); len(instanceTypes) > 0 {
+				findings = append(findings, getEMRNodeGroupFinding(role+" fleet", role+"_instance_fleet.0.instance_type_configs", instanceTypes, graviton3))
 			}
 		}
+		// Task groups added as aws_emr_instance_group and
+		// aws_emr_instance_fleet resources are analyzed on their own
+		if len(findings) == 0 {
+			continue
+		}
+		applyRoleFindings(&analysis, findings, "node groups")
+
+		if analysis.canMigrate() {
+			applyEMRReleaseLabelEligibility(&analysis, releaseLabel)
+		}
 	}
 	return analysis
 }
 
+type EMRInstanceGroupAnalyzer struct {
+	ctx *Context
+}
+
+func (a *EMRInstanceGroupAnalyzer) SupportedType() string {
+	return "aws_emr_instance_group"
+}
+
+func (a *EMRInstanceGroupAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	return analyzeEMRTaskNodes(a.ctx, resource, "instance_type", func(attributes map[string]any) []string {
+		if instanceType, ok := attributes["instance_type"].(string); ok && instanceType != "" {
+			return []string{instanceType}
+		}
+		return nil
+	})
+}
+
+type EMRInstanceFleetAnalyzer struct {
+	ctx *Context
+}
+
+func (a *EMRInstanceFleetAnalyzer) SupportedType() string {
+	return "aws_emr_instance_fleet"
+}
+
+func (a *EMRInstanceFleetAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	return analyzeEMRTaskNodes(a.ctx, resource, "instance_type_configs", getEMRInstanceTypeConfigs)
+}
+
+// analyzeEMRTaskNodes analyzes a task instance group or fleet added to a
+// cluster, checking the cluster's release label when the nodes can migrate.
+func analyzeEMRTaskNodes(ctx *Context, resource parser.TerraformResource, attribute string, getInstanceTypes func(map[string]any) []string) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		instanceTypes := getInstanceTypes(instance.Attributes)
+		if len(instanceTypes) == 0 {
+			continue
+		}
+
+		clusterID, _ := instance.Attributes["cluster_id"].(string)
+		releaseLabel := ctx.findEMRReleaseLabel(clusterID)
+		finding := getEMRNodeGroupFinding("task", attribute, instanceTypes, supportsEMRGraviton3(releaseLabel))
+		applyRoleFindings(&analysis, []roleFinding{finding}, "node groups")
+
+		if analysis.canMigrate() {
+			applyEMRReleaseLabelEligibility(&analysis, releaseLabel)
+		}
+	}
+	return analysis
+}
+
+// findEMRReleaseLabel returns the release_label of the aws_emr_cluster with
+// the given ID, or an empty string when the cluster is not in the state.
+func (c *Context) findEMRReleaseLabel(clusterID string) string {
+	for _, cluster := range c.FindResources("aws_emr_cluster") {
+		for _, instance := range cluster.Instances {
+			if id, _ := instance.Attributes["id"].(string); id == clusterID && clusterID != "" {
+				releaseLabel, _ := instance.Attributes["release_label"].(string)
+				return releaseLabel
+			}
+		}
+	}
+	return ""
+}
+
+// getEMRNodeGroupFinding makes the Graviton decision for one node group. A
+// group counts as using ARM64 only when every instance type it can launch is
+// Graviton. Graviton3 instance types are only recommended when the cluster's
+// release supports them; otherwise the Graviton2 type of the same size is.
+func getEMRNodeGroupFinding(role, attribute string, instanceTypes []string, graviton3 bool) roleFinding {
+	finding := roleFinding{Role: role, Current: strings.Join(instanceTypes, ", "), Attribute: attribute}
+
+	var recommendations, missing []string
+	var graviton int
+	for _, instanceType := range instanceTypes {
+		switch {
+		case isARM64EMRInstanceType(instanceType):
+			graviton++
+			recommendations = append(recommendations, instanceType)
+		case hasARM64EMRAlternative(instanceType):
+			alternative := getARM64EMRAlternative(instanceType)
+			if !graviton3 {
+				alternative = strings.Replace(alternative, "7g.", "6g.", 1)
+			}
+			recommendations = append(recommendations, alternative)
+		default:
+			missing = append(missing, instanceType)
+		}
+	}
+
+	switch {
+	case graviton == len(instanceTypes):
+		finding.Status = roleGraviton
+		finding.Message = "already using ARM64"
+	case len(missing) > 0:
+		finding.Status = roleBlocked
+		finding.Message = "no ARM64 compatible instance type available for " + strings.Join(missing, ", ")
+	default:
+		finding.Status = roleMigratable
+		finding.Recommendation = strings.Join(recommendations, ", ")
+		finding.Message = "can migrate to " + finding.Recommendation
+		if graviton > 0 {
+			finding.Message += " (fleet mixes ARM64 and x86_64 instance types)"
+		}
+	}
+	return finding
+}
+
+// applyEMRReleaseLabelEligibility adds a release upgrade as a prerequisite when
+// the cluster's release does not support Graviton instances.
+func applyEMRReleaseLabelEligibility(analysis *ARM64Analysis, releaseLabel string) {
+	version, found := strings.CutPrefix(releaseLabel, "emr-")
+	if !found {
+		analysis.addFinding(FindingMissingAttribute, "release_label", "release_label not in state; Graviton requires emr-5.31.0+ or emr-6.1.0+")
+		return
+	}
+	if upgradeTo := getRequiredEMRReleaseUpgrade(version, getEMRReleaseMinimums()); upgradeTo != "" {
+		analysis.addFinding(FindingPrerequisite, "release_label", fmt.Sprintf("upgrade release_label %s -> emr-%s", releaseLabel, upgradeTo))
+	}
+	if upgradeTo := getRequiredEMRReleaseUpgrade(version, getEMRGraviton3ReleaseMinimums()); upgradeTo != "" {
+		analysis.addFinding(FindingNote, "release_label", fmt.Sprintf("recommending Graviton2 instance types; Graviton3 (m7g, c7g, r7g) requires release_label emr-%s or later", upgradeTo))
+	}
+}
+
+// supportsEMRGraviton3 reports whether a release label can run Graviton3
+// instance types. A release label missing from the state does not.
+func supportsEMRGraviton3(releaseLabel string) bool {
+	version, found := strings.CutPrefix(releaseLabel, "emr-")
+	return found && getRequiredEMRReleaseUpgrade(version, getEMRGraviton3ReleaseMinimums()) == ""
+}
+
+// getRequiredEMRReleaseUpgrade returns the release to upgrade to, or an empty
+// string when the release meets the minimums. EMR release lines are major
+// versions, so each has a single minimum; releases before 5.x upgrade to the
+// oldest one and majors newer than every listed one are eligible.
+func getRequiredEMRReleaseUpgrade(version string, minimums map[string]string) string {
+	major, _, _ := strings.Cut(version, ".")
+	if minimum, found := minimums[major]; found {
+		if compareVersions(version, minimum) < 0 {
+			return minimum
+		}
+		return ""
+	}
+	if compareVersions(major, "5") < 0 {
+		return minimums["5"]
+	}
+	return ""
+}
+
+// getEMRReleaseMinimums lists the first release of each major version that
+// supports Graviton instances.
+func getEMRReleaseMinimums() map[string]string {
+	return map[string]string{
+		"5": "5.31.0",
+		"6": "6.1.0",
+	}
+}
+
+// getEMRGraviton3ReleaseMinimums lists the first release of each major version
+// that supports Graviton3 instances.
+func getEMRGraviton3ReleaseMinimums() map[string]string {
+	return map[string]string{
+		"5": "5.36.1",
+		"6": "6.9.0",
+	}
+}
+
+// getEMRInstanceGroupType returns the instance_type of a *_instance_group block.
+func getEMRInstanceGroupType(value any) string {
+	groups, ok := value.([]any)
+	if !ok || len(groups) == 0 {
+		return ""
+	}
+	group, ok := groups[0].(map[string]any)
+	if !ok {
+		return ""
+	}
+	instanceType, _ := group["instance_type"].(string)
+	return instanceType
+}
+
+// getEMRFleetInstanceTypes returns the instance types of a *_instance_fleet
+// block.
+func getEMRFleetInstanceTypes(value any) []string {
+	fleets, ok := value.([]any)
+	if !ok || len(fleets) == 0 {
+		return nil
+	}
+	fleet, ok := fleets[0].(map[string]any)
+	if !ok {
+		return nil
+	}
+	return getEMRInstanceTypeConfigs(fleet)
+}
+
+// getEMRInstanceTypeConfigs returns the instance types listed in an instance
+// fleet's instance_type_configs.
+func getEMRInstanceTypeConfigs(fleet map[string]any) []string {
+	configs, ok := fleet["instance_type_configs"].([]any)
+	if !ok {
+		return nil
+	}
+
+	var instanceTypes []string
+	for _, config := range configs {
+		configMap, ok := config.(map[string]any)
+		if !ok {
+			continue
+		}
+		if instanceType, ok := configMap["instance_type"].(string); ok && instanceType != "" {
+			instanceTypes = append(instanceTypes, instanceType)
+		}
+	}
+	return instanceTypes
+}
+
 type EMRServerlessAnalyzer struct{}
 
 func (a *EMRServerlessAnalyzer) SupportedType() string {
@@ -63,18 +280,18 @@ func (a *EMRServerlessAnalyzer) Analyze(resource parser.TerraformResource) ARM64
 	analysis := ARM64Analysis{
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
-		ARM64Compatible: true,
-		CurrentArch:     "X86_64 (default)",
 		RecommendedArch: "ARM64",
-		Notes:           "EMR Serverless supports ARM64 architecture for cost optimization",
 	}
+	analysis.setDefaultArchitecture(ArchitectureX86_64)
+	analysis.decide(StatusMigratable, FindingMigratable, "architecture", "EMR Serverless supports ARM64 architecture for cost optimization")
 
 	for _, instance := range resource.Instances {
 		if architecture, exists := instance.Attributes["architecture"]; exists {
 			if architecture == "ARM64" {
-				analysis.CurrentArch = "ARM64"
-				analysis.AlreadyUsingARM64 = true
-				analysis.Notes = "Already using ARM64 architecture"
+				analysis.setArchitecture(ArchitectureARM64)
+				analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "architecture", "Already using ARM64 architecture")
+			} else {
+				analysis.setArchitecture(ArchitectureX86_64)
 			}
 		}
 	}
@@ -0,0 +1,351 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestGetRequiredEMRReleaseUpgrade(t *testing.T) {
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"4.10.0", "5.31.0"},
+		{"5.30.2", "5.31.0"},
+		{"5.31.0", ""},
+		{"5.32.0", ""},
+		{"5.36.1", ""},
+		{"6.0.1", "6.1.0"},
+		{"6.1.0", ""},
+		{"6.15.0", ""},
+		{"7.1.0", ""},
+	}
+
+	for _, tt := range tests {
+		if got := getRequiredEMRReleaseUpgrade(tt.version, getEMRReleaseMinimums()); got != tt.expected {
+			t.Errorf("getRequiredEMRReleaseUpgrade(%q) = %q, want %q", tt.version, got, tt.expected)
+		}
+	}
+}
+
+func TestSupportsEMRGraviton3(t *testing.T) {
+	tests := []struct {
+		releaseLabel string
+		expected     bool
+	}{
+		{"emr-5.32.0", false},
+		{"emr-5.36.1", true},
+		{"emr-6.1.0", false},
+		{"emr-6.8.0", false},
+		{"emr-6.9.0", true},
+		{"emr-6.15.0", true},
+		{"emr-7.1.0", true},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := supportsEMRGraviton3(tt.releaseLabel); got != tt.expected {
+			t.Errorf("supportsEMRGraviton3(%q) = %v, want %v", tt.releaseLabel, got, tt.expected)
+		}
+	}
+}
+
+func TestEMRAnalyzer_Analyze(t *testing.T) {
+	instanceGroup := func(instanceType string) []any {
+		return []any{map[string]any{"instance_type": instanceType}}
+	}
+	instanceFleet := func(instanceTypes ...string) []any {
+		var configs []any
+		for _, instanceType := range instanceTypes {
+			configs = append(configs, map[string]any{"instance_type": instanceType})
+		}
+		return []any{map[string]any{"instance_type_configs": configs}}
+	}
+
+	tests := []struct {
+		name          string
+		attributes    map[string]any
+		expectStatus  Status
+		expectCodes   []FindingCode
+		expectMessage string
+	}{
+		{
+			name: "instance groups on a 5.x release with Graviton support",
+			attributes: map[string]any{
+				"release_label":         "emr-5.32.0",
+				"master_instance_group": instanceGroup("m5.xlarge"),
+				"core_instance_group":   instanceGroup("r5.2xlarge"),
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote, FindingNote, FindingNote},
+			expectMessage: "core (r5.2xlarge): can migrate to r6g.2xlarge",
+		},
+		{
+			name: "release with Graviton3 support",
+			attributes: map[string]any{
+				"release_label":         "emr-6.9.0",
+				"master_instance_group": instanceGroup("m5.xlarge"),
+				"core_instance_group":   instanceGroup("r5.2xlarge"),
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote, FindingNote},
+			expectMessage: "core (r5.2xlarge): can migrate to r7g.2xlarge",
+		},
+		{
+			name: "5.x release before 5.31.0",
+			attributes: map[string]any{
+				"release_label":         "emr-5.30.1",
+				"master_instance_group": instanceGroup("m5.xlarge"),
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote, FindingPrerequisite, FindingNote},
+			expectMessage: "upgrade release_label emr-5.30.1 -> emr-5.31.0",
+		},
+		{
+			name: "6.0 release",
+			attributes: map[string]any{
+				"release_label":         "emr-6.0.0",
+				"master_instance_group": instanceGroup("m5.xlarge"),
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote, FindingPrerequisite, FindingNote},
+			expectMessage: "recommending Graviton2 instance types; Graviton3 (m7g, c7g, r7g) requires release_label emr-6.9.0 or later",
+		},
+		{
+			name: "release label not in state",
+			attributes: map[string]any{
+				"master_instance_group": instanceGroup("m5.xlarge"),
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote, FindingMissingAttribute},
+			expectMessage: "master (m5.xlarge): can migrate to m6g.xlarge",
+		},
+		{
+			name: "instance fleets mixing x86_64 and Graviton",
+			attributes: map[string]any{
+				"release_label":         "emr-7.1.0",
+				"master_instance_fleet": instanceFleet("m5.xlarge", "m7g.xlarge"),
+				"core_instance_fleet":   instanceFleet("r7g.xlarge"),
+			},
+			expectStatus:  StatusPartiallyMigrated,
+			expectCodes:   []FindingCode{FindingPartiallyMigrated, FindingNote, FindingNote},
+			expectMessage: "master fleet (m5.xlarge, m7g.xlarge): can migrate to m7g.xlarge, m7g.xlarge (fleet mixes ARM64 and x86_64 instance types)",
+		},
+		{
+			name: "Graviton instance groups",
+			attributes: map[string]any{
+				"release_label":         "emr-6.1.0",
+				"master_instance_group": instanceGroup("m6g.xlarge"),
+				"core_instance_group":   instanceGroup("r7g.xlarge"),
+			},
+			expectStatus: StatusAlreadyARM64,
+			expectCodes:  []FindingCode{FindingAlreadyARM64, FindingNote, FindingNote},
+		},
+		{
+			name: "core group without a Graviton alternative",
+			attributes: map[string]any{
+				"release_label":         "emr-6.1.0",
+				"master_instance_group": instanceGroup("m6g.xlarge"),
+				"core_instance_group":   instanceGroup("p3.2xlarge"),
+			},
+			expectStatus: StatusBlocked,
+			expectCodes:  []FindingCode{FindingNoARM64Option, FindingNote, FindingNoARM64Option},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &EMRAnalyzer{}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type:      "aws_emr_cluster",
+				Name:      "main",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v (notes: %s)", analysis.Status, tt.expectStatus, analysis.Notes)
+			}
+			if codes := getFindingCodes(analysis); !slices.Equal(codes, tt.expectCodes) {
+				t.Errorf("finding codes = %v, want %v", codes, tt.expectCodes)
+			}
+			if tt.expectMessage != "" && !slices.ContainsFunc(analysis.Findings, func(finding Finding) bool { return finding.Message == tt.expectMessage }) {
+				t.Errorf("Findings = %+v, want message %q", analysis.Findings, tt.expectMessage)
+			}
+		})
+	}
+}
+
+func TestEMRTaskNodeAnalyzers_Analyze(t *testing.T) {
+	cluster := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_emr_cluster",
+		Name: "main",
+		Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+			"id":            "j-123",
+			"release_label": "emr-6.15.0",
+		}}},
+	}
+	ctx := NewContext(&parser.TerraformState{Version: 4, Resources: []parser.TerraformResource{cluster}})
+
+	tests := []struct {
+		name            string
+		resource        parser.TerraformResource
+		expectStatus    Status
+		expectAttribute string
+		expectCodes     []FindingCode
+	}{
+		{
+			name: "task instance group",
+			resource: parser.TerraformResource{
+				Type: "aws_emr_instance_group",
+				Name: "task",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"cluster_id":    "j-123",
+					"instance_type": "c5.2xlarge",
+				}}},
+			},
+			expectStatus:    StatusMigratable,
+			expectAttribute: "instance_type",
+			expectCodes:     []FindingCode{FindingMigratable, FindingNote},
+		},
+		{
+			name: "task instance fleet",
+			resource: parser.TerraformResource{
+				Type: "aws_emr_instance_fleet",
+				Name: "task",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"cluster_id": "j-123",
+					"instance_type_configs": []any{
+						map[string]any{"instance_type": "c5.2xlarge"},
+						map[string]any{"instance_type": "m5.2xlarge"},
+					},
+				}}},
+			},
+			expectStatus:    StatusMigratable,
+			expectAttribute: "instance_type_configs",
+			expectCodes:     []FindingCode{FindingMigratable, FindingNote},
+		},
+		{
+			name: "task instance group of a cluster not in state",
+			resource: parser.TerraformResource{
+				Type: "aws_emr_instance_group",
+				Name: "orphan",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"cluster_id":    "j-456",
+					"instance_type": "c5.2xlarge",
+				}}},
+			},
+			expectStatus:    StatusMigratable,
+			expectAttribute: "instance_type",
+			expectCodes:     []FindingCode{FindingMigratable, FindingNote, FindingMissingAttribute},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResourceWithContext(tt.resource, ctx)
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v (notes: %s)", analysis.Status, tt.expectStatus, analysis.Notes)
+			}
+			if codes := getFindingCodes(analysis); !slices.Equal(codes, tt.expectCodes) {
+				t.Errorf("finding codes = %v, want %v", codes, tt.expectCodes)
+			}
+			if len(analysis.Findings) > 1 && analysis.Findings[1].Attribute != tt.expectAttribute {
+				t.Errorf("node group finding attribute = %q, want %q", analysis.Findings[1].Attribute, tt.expectAttribute)
+			}
+		})
+	}
+
+	if got := (&EMRInstanceFleetAnalyzer{}).SupportedType(); got != "aws_emr_instance_fleet" {
+		t.Errorf("EMRInstanceFleetAnalyzer.SupportedType() = %q, want aws_emr_instance_fleet", got)
+	}
+}
+
+func TestEMRAnalyzer_TaskGroupsAnalyzedOnTheirOwn(t *testing.T) {
+	cluster := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_emr_cluster",
+		Name: "main",
+		Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+			"id":                    "j-123",
+			"release_label":         "emr-6.15.0",
+			"master_instance_group": []any{map[string]any{"instance_type": "m6g.xlarge"}},
+			"core_instance_group":   []any{map[string]any{"instance_type": "r6g.xlarge"}},
+		}}},
+	}
+	task := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_emr_instance_group",
+		Name: "task",
+		Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+			"cluster_id":    "j-123",
+			"instance_type": "c5.2xlarge",
+		}}},
+	}
+	ctx := NewContext(&parser.TerraformState{Version: 4, Resources: []parser.TerraformResource{cluster, task}})
+
+	// The x86_64 task group is reported by its own resource, so it must not
+	// turn the cluster's Graviton master and core groups into a partial
+	// migration and be counted twice
+	analysis := AnalyzeResourceWithContext(cluster, ctx)
+	if analysis.Status != StatusAlreadyARM64 {
+		t.Errorf("cluster Status = %v, want %v (notes: %s)", analysis.Status, StatusAlreadyARM64, analysis.Notes)
+	}
+	if codes := getFindingCodes(analysis); !slices.Equal(codes, []FindingCode{FindingAlreadyARM64, FindingNote, FindingNote}) {
+		t.Errorf("cluster finding codes = %v, want only the master and core groups", codes)
+	}
+
+	if analysis := AnalyzeResourceWithContext(task, ctx); analysis.Status != StatusMigratable {
+		t.Errorf("task group Status = %v, want %v", analysis.Status, StatusMigratable)
+	}
+}
+
+func TestEMRServerlessAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name          string
+		attributes    map[string]any
+		expectStatus  Status
+		expectArch    Architecture
+		expectDefault bool
+	}{
+		{
+			name:          "architecture defaults to X86_64",
+			attributes:    map[string]any{"type": "spark"},
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectDefault: true,
+		},
+		{
+			name:         "explicit X86_64",
+			attributes:   map[string]any{"type": "spark", "architecture": "X86_64"},
+			expectStatus: StatusMigratable,
+			expectArch:   ArchitectureX86_64,
+		},
+		{
+			name:         "ARM64",
+			attributes:   map[string]any{"type": "hive", "architecture": "ARM64"},
+			expectStatus: StatusAlreadyARM64,
+			expectArch:   ArchitectureARM64,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := (&EMRServerlessAnalyzer{}).Analyze(parser.TerraformResource{
+				Type:      "aws_emrserverless_application",
+				Name:      "app",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v", analysis.Status, tt.expectStatus)
+			}
+			if analysis.Architecture != tt.expectArch || analysis.ArchitectureDefault != tt.expectDefault {
+				t.Errorf("Architecture = %v (default %v), want %v (default %v)", analysis.Architecture, analysis.ArchitectureDefault, tt.expectArch, tt.expectDefault)
+			}
+		})
+	}
+}
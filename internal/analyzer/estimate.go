@@ -0,0 +1,154 @@
+package analyzer
+
+// Confidence is how much of the evidence a recommendation depends on the
+// analyzer could see, such as the AMI, container images or engine version.
+type Confidence string
+
+const (
+	ConfidenceHigh   Confidence = "high"
+	ConfidenceMedium Confidence = "medium"
+	ConfidenceLow    Confidence = "low"
+)
+
+// maxEffort caps the effort score.
+const maxEffort = 5
+
+// migrationProfile describes what moving a resource type to ARM64 typically
+// involves.
+type migrationProfile struct {
+	// Effort ranks the work and risk of the switch, from 1 for a
+	// configuration change applied in place to 4 for replacing single
+	// instances behind a new machine image
+	Effort int
+	// SavingsPercent is the typical list price difference of the ARM64
+	// option; zero for resources that only enable other migrations or whose
+	// cost is already counted on the resources they reserve capacity for
+	SavingsPercent float64
+}
+
+// getMigrationProfiles returns the migration profile of every supported
+// resource type.
+func getMigrationProfiles() map[string]migrationProfile {
+	ec2Capacity := migrationProfile{Effort: 3, SavingsPercent: 20}
+	database := migrationProfile{Effort: 3, SavingsPercent: 10}
+	cache := migrationProfile{Effort: 3, SavingsPercent: 5}
+	workload := migrationProfile{Effort: 2}
+
+	return map[string]migrationProfile{
+		// Amazon EC2 and Auto Scaling
+		"aws_instance":             {Effort: 4, SavingsPercent: 20},
+		"aws_launch_template":      ec2Capacity,
+		"aws_autoscaling_group":    ec2Capacity,
+		"aws_launch_configuration": ec2Capacity,
+		"aws_ec2_fleet":            ec2Capacity,
+		"aws_spot_fleet_request":   ec2Capacity,
+		// Reservations only follow the instances that use them, which carry
+		// the savings
+		"aws_ec2_capacity_reservation": {Effort: 2},
+		"aws_ec2_host":                 {Effort: 2},
+		// EC2 Image Builder produces the arm64 AMIs other migrations need
+		"aws_imagebuilder_image_recipe":                 {Effort: 3},
+		"aws_imagebuilder_infrastructure_configuration": {Effort: 1},
+		"aws_imagebuilder_image_pipeline":               {Effort: 2},
+		// Containers and serverless
+		"aws_ecs_task_definition":            {Effort: 2, SavingsPercent: 20},
+		"aws_ecs_service":                    {Effort: 2, SavingsPercent: 20},
+		"aws_ecs_capacity_provider":          {Effort: 3},
+		"aws_ecs_cluster_capacity_providers": {Effort: 3},
+		"aws_lambda_function":                {Effort: 1, SavingsPercent: 20},
+		"aws_codebuild_project":              {Effort: 2, SavingsPercent: 20},
+		"aws_codebuild_fleet":                {Effort: 2, SavingsPercent: 20},
+		"aws_batch_compute_environment":      {Effort: 3, SavingsPercent: 20},
+		"aws_batch_job_definition":           {Effort: 2, SavingsPercent: 20},
+		"aws_elastic_beanstalk_environment":  {Effort: 2, SavingsPercent: 20},
+		"aws_gamelift_fleet":                 {Effort: 4, SavingsPercent: 20},
+		// Managed databases change instance class in a maintenance window
+		"aws_db_instance":                          database,
+		"aws_rds_cluster":                          database,
+		"aws_rds_cluster_instance":                 database,
+		"aws_docdb_cluster_instance":               database,
+		"aws_neptune_cluster_instance":             database,
+		"aws_elasticache_cluster":                  cache,
+		"aws_elasticache_replication_group":        cache,
+		"aws_elasticache_global_replication_group": {Effort: 3},
+		"aws_elasticache_serverless_cache":         {Effort: 1},
+		"aws_memorydb_cluster":                     cache,
+		"aws_opensearch_domain":                    {Effort: 3, SavingsPercent: 10},
+		"aws_elasticsearch_domain":                 {Effort: 3, SavingsPercent: 10},
+		"aws_msk_cluster":                          {Effort: 3, SavingsPercent: 10},
+		// Analytics and machine learning
+		"aws_eks_node_group":                   {Effort: 3, SavingsPercent: 20},
+		"aws_emr_cluster":                      {Effort: 3, SavingsPercent: 20},
+		"aws_emr_instance_group":               {Effort: 3, SavingsPercent: 20},
+		"aws_emr_instance_fleet":               {Effort: 3, SavingsPercent: 20},
+		"aws_emrserverless_application":        {Effort: 1, SavingsPercent: 20},
+		"aws_sagemaker_endpoint_configuration": {Effort: 3, SavingsPercent: 15},
+		// Kubernetes workloads enable node migrations rather than save by
+		// themselves
+		"kubernetes_deployment":    workload,
+		"kubernetes_deployment_v1": workload,
+		"kubernetes_daemonset":     workload,
+		"kubernetes_daemon_set_v1": workload,
+		"kubernetes_manifest":      workload,
+		"helm_release":             workload,
+		// Google Cloud
+		"google_compute_instance":          {Effort: 4, SavingsPercent: 20},
+		"google_compute_instance_template": {Effort: 3, SavingsPercent: 20},
+		"google_container_node_pool":       {Effort: 3, SavingsPercent: 20},
+		// Azure
+		"azurerm_linux_virtual_machine":           {Effort: 4, SavingsPercent: 15},
+		"azurerm_linux_virtual_machine_scale_set": {Effort: 3, SavingsPercent: 15},
+		"azurerm_kubernetes_cluster_node_pool":    {Effort: 3, SavingsPercent: 15},
+	}
+}
+
+// estimateMigration scores the effort of migrating a resource, how confident
+// the analysis is, and the typical savings. Prerequisite findings each add a
+// step to the effort; missing attributes and unverified images, AMIs or
+// layers lower the confidence. Roll-ups of resources that are analyzed on
+// their own carry no savings, so they are not counted twice.
+func estimateMigration(analysis *ARM64Analysis) {
+	profile, known := getMigrationProfiles()[analysis.ResourceType]
+	if !known || analysis.Status == StatusUnsupported {
+		return
+	}
+
+	gaps := 0
+	for _, finding := range analysis.Findings {
+		if finding.Code == FindingMissingAttribute || finding.Code == FindingUnverified {
+			gaps++
+		}
+	}
+
+	switch {
+	case analysis.Status == StatusUnknown || gaps > 1:
+		analysis.Confidence = ConfidenceLow
+	case gaps == 1:
+		analysis.Confidence = ConfidenceMedium
+	default:
+		analysis.Confidence = ConfidenceHigh
+	}
+
+	if !analysis.canMigrate() {
+		return
+	}
+	analysis.Effort = profile.Effort
+	for _, finding := range analysis.Findings {
+		if finding.Code == FindingPrerequisite {
+			analysis.Effort++
+		}
+	}
+	analysis.Effort = min(analysis.Effort, maxEffort)
+	if !analysis.RollUp {
+		analysis.EstimatedSavingsPercent = profile.SavingsPercent
+	}
+}
+
+// SavingsPerEffort ranks migration opportunities: the typical savings divided
+// by the effort score, or zero for resources with nothing to migrate.
+func (a ARM64Analysis) SavingsPerEffort() float64 {
+	if a.Effort == 0 {
+		return 0
+	}
+	return a.EstimatedSavingsPercent / float64(a.Effort)
+}
@@ -0,0 +1,221 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestAnalyzeResource_Estimate(t *testing.T) {
+	tests := []struct {
+		name             string
+		resource         parser.TerraformResource
+		expectEffort     int
+		expectConfidence Confidence
+		expectSavings    float64
+	}{
+		{
+			name: "zip Lambda",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_lambda_function",
+				Name: "api",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"runtime":       "python3.12",
+					"architectures": []any{"x86_64"},
+				}}},
+			},
+			expectEffort:     1,
+			expectConfidence: ConfidenceHigh,
+			expectSavings:    20,
+		},
+		{
+			name: "container image Lambda with an uninspected image",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_lambda_function",
+				Name: "worker",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"package_type":  "Image",
+					"image_uri":     "123456789012.dkr.ecr.us-east-1.amazonaws.com/worker:latest",
+					"architectures": []any{"x86_64"},
+				}}},
+			},
+			expectEffort:     2,
+			expectConfidence: ConfidenceMedium,
+			expectSavings:    20,
+		},
+		{
+			name: "RDS instance with engine version checked",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_db_instance",
+				Name: "main",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"engine":         "mysql",
+					"engine_version": "8.0.35",
+					"instance_class": "db.m5.large",
+				}}},
+			},
+			expectEffort:     3,
+			expectConfidence: ConfidenceHigh,
+			expectSavings:    10,
+		},
+		{
+			name: "RDS instance needing an engine upgrade",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_db_instance",
+				Name: "legacy",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"engine":         "mysql",
+					"engine_version": "8.0.15",
+					"instance_class": "db.m5.large",
+				}}},
+			},
+			expectEffort:     4,
+			expectConfidence: ConfidenceHigh,
+			expectSavings:    10,
+		},
+		{
+			name: "RDS instance without engine version",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_db_instance",
+				Name: "unknown_version",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"engine":         "mysql",
+					"instance_class": "db.m5.large",
+				}}},
+			},
+			expectEffort:     3,
+			expectConfidence: ConfidenceMedium,
+			expectSavings:    10,
+		},
+		{
+			name: "EC2 instance with an unresolved AMI",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_instance",
+				Name: "web",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"ami":           "ami-0123456789abcdef0",
+					"instance_type": "m5.large",
+				}}},
+			},
+			expectEffort:     4,
+			expectConfidence: ConfidenceMedium,
+			expectSavings:    20,
+		},
+		{
+			name: "capacity reservation carries no savings",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_ec2_capacity_reservation",
+				Name: "web",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"instance_type": "m5.large",
+				}}},
+			},
+			expectEffort:     2,
+			expectConfidence: ConfidenceHigh,
+			expectSavings:    0,
+		},
+		{
+			name: "EC2 instance already on Graviton",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_instance",
+				Name: "arm",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"instance_type": "m7g.large",
+				}}},
+			},
+			expectEffort:     0,
+			expectConfidence: ConfidenceHigh,
+			expectSavings:    0,
+		},
+		{
+			name: "fleet without launch template config",
+			resource: parser.TerraformResource{
+				Mode:      "managed",
+				Type:      "aws_ec2_fleet",
+				Name:      "empty",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{}}},
+			},
+			expectEffort:     0,
+			expectConfidence: ConfidenceLow,
+			expectSavings:    0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResource(tt.resource)
+
+			if analysis.Effort != tt.expectEffort {
+				t.Errorf("Effort = %d, want %d (notes: %s)", analysis.Effort, tt.expectEffort, analysis.Notes)
+			}
+			if analysis.Confidence != tt.expectConfidence {
+				t.Errorf("Confidence = %v, want %v (notes: %s)", analysis.Confidence, tt.expectConfidence, analysis.Notes)
+			}
+			if analysis.EstimatedSavingsPercent != tt.expectSavings {
+				t.Errorf("EstimatedSavingsPercent = %v, want %v", analysis.EstimatedSavingsPercent, tt.expectSavings)
+			}
+		})
+	}
+}
+
+func TestAnalyzeResourceWithContext_RollUpSavings(t *testing.T) {
+	member := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_rds_cluster_instance",
+		Name: "writer",
+		Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+			"cluster_identifier": "main",
+			"engine":             "aurora-postgresql",
+			"engine_version":     "15.4",
+			"instance_class":     "db.r5.large",
+			"writer":             true,
+		}}},
+	}
+	cluster := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_rds_cluster",
+		Name: "main",
+		Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+			"cluster_identifier": "main",
+			"engine":             "aurora-postgresql",
+		}}},
+	}
+	ctx := NewContext(&parser.TerraformState{Version: 4, Resources: []parser.TerraformResource{cluster, member}})
+
+	rollUp := AnalyzeResourceWithContext(cluster, ctx)
+	if !rollUp.RollUp || rollUp.EstimatedSavingsPercent != 0 {
+		t.Errorf("cluster RollUp = %v, EstimatedSavingsPercent = %v, want a roll-up without savings", rollUp.RollUp, rollUp.EstimatedSavingsPercent)
+	}
+	if instance := AnalyzeResourceWithContext(member, ctx); instance.EstimatedSavingsPercent != 10 {
+		t.Errorf("instance EstimatedSavingsPercent = %v, want 10", instance.EstimatedSavingsPercent)
+	}
+}
+
+func TestGetMigrationProfiles_CoverSupportedTypes(t *testing.T) {
+	for resourceType, profile := range getMigrationProfiles() {
+		analysis := AnalyzeResource(parser.TerraformResource{Type: resourceType, Name: "test"})
+		if !analysis.Supported {
+			t.Errorf("migration profile for unsupported resource type %s", resourceType)
+		}
+		if profile.Effort < 1 || profile.Effort > maxEffort {
+			t.Errorf("migration profile for %s has effort %d outside 1-%d", resourceType, profile.Effort, maxEffort)
+		}
+	}
+}
+
+func TestARM64Analysis_SavingsPerEffort(t *testing.T) {
+	if got := (ARM64Analysis{Effort: 4, EstimatedSavingsPercent: 20}).SavingsPerEffort(); got != 5 {
+		t.Errorf("SavingsPerEffort() = %v, want 5", got)
+	}
+	if got := (ARM64Analysis{}).SavingsPerEffort(); got != 0 {
+		t.Errorf("SavingsPerEffort() = %v, want 0", got)
+	}
+}
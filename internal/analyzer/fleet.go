@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type EC2FleetAnalyzer struct {
+	ctx *Context
+}
+
+func (a *EC2FleetAnalyzer) SupportedType() string {
+	return "aws_ec2_fleet"
+}
+
+func (a *EC2FleetAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		configs, _ := instance.Attributes["launch_template_config"].([]any)
+		if len(configs) == 0 {
+			markUnknown(&analysis, "launch_template_config", "No launch_template_config found for this fleet")
+			continue
+		}
+		applyCapacity(&analysis, a.ctx.resolveFleetCapacity(configs, "launch_template_id", "launch_template_name", "override"))
+	}
+	return analysis
+}
+
+type SpotFleetRequestAnalyzer struct {
+	ctx *Context
+}
+
+func (a *SpotFleetRequestAnalyzer) SupportedType() string {
+	return "aws_spot_fleet_request"
+}
+
+func (a *SpotFleetRequestAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		if configs, ok := instance.Attributes["launch_template_config"].([]any); ok && len(configs) > 0 {
+			applyCapacity(&analysis, a.ctx.resolveFleetCapacity(configs, "id", "name", "overrides"))
+			continue
+		}
+
+		specifications, _ := instance.Attributes["launch_specification"].([]any)
+		if len(specifications) == 0 {
+			markUnknown(&analysis, "launch_template_config", "No launch_template_config or launch_specification found for this fleet")
+			continue
+		}
+		applyCapacity(&analysis, getLaunchSpecificationCapacity(specifications))
+	}
+	return analysis
+}
+
+// resolveFleetCapacity merges the launch template configs of an EC2 Fleet or
+// Spot Fleet. The two resources name the launch template and override
+// attributes differently, so the caller passes those keys.
+func (c *Context) resolveFleetCapacity(configs []any, idKey, nameKey, overridesKey string) asgCapacity {
+	merged := asgCapacity{Attribute: "launch_template_config", SharedAMI: len(configs) == 1}
+	for _, config := range configs {
+		configMap, ok := config.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		spec := getFirstBlock(configMap["launch_template_specification"])
+		id, _ := spec[idKey].(string)
+		name, _ := spec[nameKey].(string)
+		capacity, _ := c.resolveLaunchTemplateCapacity(id, name)
+		if merged.Source == "" {
+			merged.Source = capacity.Source
+		} else {
+			merged.Source += ", " + capacity.Source
+		}
+		merged.Findings = append(merged.Findings, capacity.Findings...)
+
+		overrides, _ := configMap[overridesKey].([]any)
+		if len(overrides) == 0 {
+			merged.InstanceTypes = append(merged.InstanceTypes, capacity.InstanceTypes...)
+			merged.CPUManufacturers = append(merged.CPUManufacturers, capacity.CPUManufacturers...)
+			merged.UsesRequirements = merged.UsesRequirements || capacity.UsesRequirements
+			continue
+		}
+		for _, override := range overrides {
+			overrideMap, ok := override.(map[string]any)
+			if !ok {
+				continue
+			}
+			if instanceType, ok := overrideMap["instance_type"].(string); ok && instanceType != "" {
+				merged.InstanceTypes = append(merged.InstanceTypes, instanceType)
+			}
+			if requirements := getFirstBlock(overrideMap["instance_requirements"]); requirements != nil {
+				merged.UsesRequirements = true
+				merged.CPUManufacturers = append(merged.CPUManufacturers, getStringList(requirements["cpu_manufacturers"])...)
+			}
+		}
+	}
+	return merged
+}
+
+// getLaunchSpecificationCapacity reads the legacy launch_specification blocks
+// of a Spot Fleet, each of which names its own AMI.
+func getLaunchSpecificationCapacity(specifications []any) asgCapacity {
+	capacity := asgCapacity{Source: "launch_specification", Attribute: "launch_specification", SharedAMI: true}
+	var firstAMI string
+	for _, specification := range specifications {
+		specificationMap, ok := specification.(map[string]any)
+		if !ok {
+			continue
+		}
+		if instanceType, ok := specificationMap["instance_type"].(string); ok && instanceType != "" {
+			capacity.InstanceTypes = append(capacity.InstanceTypes, instanceType)
+		}
+		ami, _ := specificationMap["ami"].(string)
+		if firstAMI == "" {
+			firstAMI = ami
+		} else if ami != firstAMI {
+			capacity.SharedAMI = false
+		}
+	}
+	return capacity
+}
@@ -0,0 +1,108 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestFleetAnalyzers_Analyze(t *testing.T) {
+	launchTemplate := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_launch_template",
+		Name: "batch",
+		Instances: []parser.ResourceInstance{
+			{
+				Attributes: map[string]interface{}{
+					"id":            "lt-0123456789abcdef0",
+					"name":          "batch",
+					"instance_type": "c5.large",
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		resourceType    string
+		attributes      map[string]interface{}
+		expectARM64     bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name:         "EC2 Fleet overrides",
+			resourceType: "aws_ec2_fleet",
+			attributes: map[string]interface{}{
+				"launch_template_config": []any{
+					map[string]any{
+						"launch_template_specification": []any{
+							map[string]any{"launch_template_id": "lt-0123456789abcdef0"},
+						},
+						"override": []any{
+							map[string]any{"instance_type": "c5.large"},
+							map[string]any{"instance_type": "c6i.large"},
+						},
+					},
+				},
+			},
+			expectARM64:     true,
+			expectRecommend: "c7g.large, c8g.large",
+			expectNotes:     "Requires an arm64 AMI in aws_launch_template.batch",
+		},
+		{
+			name:         "Spot Fleet overrides mixing architectures",
+			resourceType: "aws_spot_fleet_request",
+			attributes: map[string]interface{}{
+				"launch_template_config": []any{
+					map[string]any{
+						"launch_template_specification": []any{
+							map[string]any{"name": "batch"},
+						},
+						"overrides": []any{
+							map[string]any{"instance_type": "c5.large"},
+							map[string]any{"instance_type": "c7g.large"},
+						},
+					},
+				},
+			},
+			expectARM64: false,
+			expectNotes: "Misconfigured: overrides mix arm64 (c7g.large) and x86_64 (c5.large)",
+		},
+		{
+			name:         "Spot Fleet launch specifications with per-architecture AMIs",
+			resourceType: "aws_spot_fleet_request",
+			attributes: map[string]interface{}{
+				"launch_specification": []any{
+					map[string]any{"instance_type": "m5.large", "ami": "ami-x86"},
+					map[string]any{"instance_type": "m7g.large", "ami": "ami-arm"},
+				},
+			},
+			expectARM64:     true,
+			expectRecommend: "m7g.large",
+			expectNotes:     "Can migrate to ARM64 instance types",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &parser.TerraformState{Version: 4, Resources: []parser.TerraformResource{launchTemplate}}
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Type:      tt.resourceType,
+				Name:      "fleet",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			}, NewContext(state))
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+package analyzer
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type GCPComputeInstanceAnalyzer struct{}
+
+func (a *GCPComputeInstanceAnalyzer) SupportedType() string {
+	return "google_compute_instance"
+}
+
+// Analyze handles google_compute_instance and google_compute_instance_template,
+// which differ only in where they declare the boot disk image.
+func (a *GCPComputeInstanceAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		machineType, ok := instance.Attributes["machine_type"].(string)
+		if !ok || machineType == "" {
+			continue
+		}
+
+		image := getGCPBootImage(instance.Attributes)
+		if strings.Contains(strings.ToLower(image), "windows") {
+			markNotApplicableWindows(&analysis, image)
+			continue
+		}
+
+		applyGCPMachineType(&analysis, "machine_type", getGCPMachineTypeName(machineType), image, hasGuestAccelerator(instance.Attributes))
+	}
+	return analysis
+}
+
+type GKENodePoolAnalyzer struct{}
+
+func (a *GKENodePoolAnalyzer) SupportedType() string {
+	return "google_container_node_pool"
+}
+
+func (a *GKENodePoolAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		nodeConfig := getFirstBlock(instance.Attributes["node_config"])
+		imageType, _ := nodeConfig["image_type"].(string)
+		if strings.HasPrefix(strings.ToUpper(imageType), "WINDOWS") {
+			markNotApplicableWindows(&analysis, imageType)
+			continue
+		}
+
+		// GKE creates node pools with e2-medium nodes unless told otherwise
+		machineType, _ := nodeConfig["machine_type"].(string)
+		defaulted := machineType == ""
+		if defaulted {
+			machineType = "e2-medium"
+		}
+
+		// GKE picks the arm64 variant of the node image itself
+		applyGCPMachineType(&analysis, "node_config.0.machine_type", machineType, "", hasGuestAccelerator(nodeConfig))
+		if defaulted && analysis.Architecture == ArchitectureX86_64 {
+			analysis.setDefaultArchitecture(ArchitectureX86_64)
+		}
+		if !analysis.canMigrate() {
+			continue
+		}
+		if imageType != "" && !strings.HasSuffix(strings.ToUpper(imageType), "_CONTAINERD") {
+			analysis.addFinding(FindingPrerequisite, "node_config.0.image_type", "switch image_type "+imageType+" to COS_CONTAINERD; Arm nodes only run containerd images")
+		}
+		analysis.addFinding(FindingNote, "", "GKE taints Arm nodes with kubernetes.io/arch=arm64:NoSchedule; workloads need arm64 images and a matching toleration or node selector")
+	}
+	return analysis
+}
+
+// applyGCPMachineType records the decision for a machine type and, for
+// instances, the architecture of the boot disk image it starts from. An empty
+// image skips the image check.
+func applyGCPMachineType(analysis *ARM64Analysis, attribute, machineType, image string, accelerated bool) {
+	imageArch := getGCPImageArch(image)
+
+	if isARM64GCPMachineType(machineType) {
+		analysis.setArchitecture(ArchitectureARM64)
+		if imageArch == ArchitectureX86_64 {
+			analysis.decide(StatusMisconfigured, FindingMisconfigured, "boot_disk", "Arm machine type "+machineType+" boots x86_64 image "+image)
+			return
+		}
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, attribute, "Already using Arm machine type")
+		return
+	}
+
+	analysis.setArchitecture(ArchitectureX86_64)
+	if imageArch == ArchitectureARM64 {
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, "boot_disk", "x86_64 machine type "+machineType+" boots arm64 image "+image)
+		return
+	}
+	if accelerated {
+		analysis.decide(StatusBlocked, FindingBlocked, "guest_accelerator", "Arm machine types do not support guest_accelerator")
+		return
+	}
+	alternative := getGCPArmMachineType(machineType)
+	if alternative == "" {
+		analysis.decide(StatusBlocked, FindingNoARM64Option, attribute, "No Arm machine type available for "+machineType)
+		return
+	}
+
+	analysis.RecommendedArch = alternative
+	analysis.decide(StatusMigratable, FindingMigratable, attribute, fmt.Sprintf("Can migrate to Arm machine type %s", alternative))
+	if image != "" {
+		analysis.addFinding(FindingUnverified, "boot_disk", "Requires an arm64 boot disk image in place of "+image)
+	}
+}
+
+// getGCPBootImage returns the boot disk image of an instance, from
+// boot_disk.initialize_params, or of an instance template, from its boot disk.
+func getGCPBootImage(attributes map[string]any) string {
+	if bootDisk := getFirstBlock(attributes["boot_disk"]); bootDisk != nil {
+		image, _ := getFirstBlock(bootDisk["initialize_params"])["image"].(string)
+		return image
+	}
+
+	disks, _ := attributes["disk"].([]any)
+	for _, disk := range disks {
+		diskMap, ok := disk.(map[string]any)
+		if !ok {
+			continue
+		}
+		if boot, _ := diskMap["boot"].(bool); boot || len(disks) == 1 {
+			image, _ := diskMap["source_image"].(string)
+			return image
+		}
+	}
+	return ""
+}
+
+// getGCPImageArch infers the architecture of a public image from its name or
+// family, e.g. "debian-cloud/debian-12-arm64". Public x86_64 images carry no
+// architecture suffix, so names without one, including custom images, return
+// ArchitectureUnknown.
+func getGCPImageArch(image string) Architecture {
+	image = strings.ToLower(image)
+	switch {
+	case strings.Contains(image, "arm64"), strings.Contains(image, "aarch64"):
+		return ArchitectureARM64
+	case strings.Contains(image, "x86-64"), strings.Contains(image, "amd64"):
+		return ArchitectureX86_64
+	default:
+		return ArchitectureUnknown
+	}
+}
+
+func hasGuestAccelerator(attributes map[string]any) bool {
+	accelerators, _ := attributes["guest_accelerator"].([]any)
+	return len(accelerators) > 0
+}
+
+// getGCPMachineTypeName strips the zone URL from a machine type, e.g.
+// "zones/us-central1-a/machineTypes/n2-standard-4" -> "n2-standard-4".
+func getGCPMachineTypeName(machineType string) string {
+	return machineType[strings.LastIndex(machineType, "/")+1:]
+}
+
+func isARM64GCPMachineType(machineType string) bool {
+	return strings.HasPrefix(machineType, "t2a-") || strings.HasPrefix(machineType, "c4a-")
+}
+
+// getGCPX86ToArmSeriesMap maps x86 machine series to the Arm series that
+// replaces them: Tau T2D scale-out workloads move to Tau T2A, and general
+// purpose and compute optimized series move to Axion C4A.
+func getGCPX86ToArmSeriesMap() map[string]string {
+	return map[string]string{
+		"e2":  "c4a",
+		"n1":  "c4a",
+		"n2":  "c4a",
+		"n2d": "c4a",
+		"n4":  "c4a",
+		"c3":  "c4a",
+		"c3d": "c4a",
+		"c4":  "c4a",
+		"t2d": "t2a",
+	}
+}
+
+// getGCPArmMachineShapes lists the vCPU counts offered by each Arm series and
+// machine class.
+func getGCPArmMachineShapes() map[string][]int {
+	return map[string][]int{
+		"t2a-standard": {1, 2, 4, 8, 16, 32, 48},
+		"c4a-standard": {1, 2, 4, 8, 16, 32, 48, 64, 72},
+		"c4a-highcpu":  {1, 2, 4, 8, 16, 32, 48, 64, 72},
+		"c4a-highmem":  {1, 2, 4, 8, 16, 32, 48, 64, 72},
+	}
+}
+
+// getGCPArmMachineType returns the smallest Arm machine type of the same
+// class with at least as many vCPUs, e.g. "n2-standard-4" -> "c4a-standard-4".
+// Shared-core E2 types map to t2a-standard-1; custom machine types have no
+// Arm equivalent.
+func getGCPArmMachineType(machineType string) string {
+	switch machineType {
+	case "e2-micro", "e2-small", "e2-medium":
+		return "t2a-standard-1"
+	}
+
+	parts := strings.Split(machineType, "-")
+	if len(parts) != 3 {
+		return ""
+	}
+	series, class := parts[0], parts[1]
+	vcpus, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return ""
+	}
+	armSeries, ok := getGCPX86ToArmSeriesMap()[series]
+	if !ok {
+		return ""
+	}
+
+	// T2A only offers up to 48 vCPUs; larger T2D shapes fall back to C4A
+	for _, candidate := range slices.Compact([]string{armSeries, "c4a"}) {
+		for _, size := range getGCPArmMachineShapes()[candidate+"-"+class] {
+			if size >= vcpus {
+				return fmt.Sprintf("%s-%s-%d", candidate, class, size)
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,224 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestGCPAnalyzers_Analyze(t *testing.T) {
+	tests := []struct {
+		name            string
+		resourceType    string
+		attributes      map[string]interface{}
+		expectARM64     bool
+		expectAlready   bool
+		expectNA        bool
+		expectArch      string
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name:         "N2 instance with Debian image",
+			resourceType: "google_compute_instance",
+			attributes: map[string]interface{}{
+				"machine_type": "n2-standard-4",
+				"boot_disk": []any{
+					map[string]any{
+						"initialize_params": []any{
+							map[string]any{"image": "debian-cloud/debian-12"},
+						},
+					},
+				},
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "c4a-standard-4",
+			expectNotes:     "Requires an arm64 boot disk image in place of debian-cloud/debian-12",
+		},
+		{
+			name:         "T2D instance moves to T2A",
+			resourceType: "google_compute_instance",
+			attributes: map[string]interface{}{
+				"machine_type": "zones/us-central1-a/machineTypes/t2d-standard-8",
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "t2a-standard-8",
+			expectNotes:     "Can migrate to Arm machine type t2a-standard-8",
+		},
+		{
+			name:         "Large T2D instance falls back to C4A",
+			resourceType: "google_compute_instance",
+			attributes: map[string]interface{}{
+				"machine_type": "t2d-standard-60",
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "c4a-standard-64",
+		},
+		{
+			name:         "C4A instance",
+			resourceType: "google_compute_instance",
+			attributes: map[string]interface{}{
+				"machine_type": "c4a-standard-4",
+				"boot_disk": []any{
+					map[string]any{
+						"initialize_params": []any{
+							map[string]any{"image": "debian-cloud/debian-12-arm64"},
+						},
+					},
+				},
+			},
+			expectARM64:     true,
+			expectAlready:   true,
+			expectArch:      "ARM64",
+			expectRecommend: "ARM64",
+			expectNotes:     "Already using Arm machine type",
+		},
+		{
+			name:         "x86 instance booting an arm64 image",
+			resourceType: "google_compute_instance",
+			attributes: map[string]interface{}{
+				"machine_type": "e2-standard-2",
+				"boot_disk": []any{
+					map[string]any{
+						"initialize_params": []any{
+							map[string]any{"image": "ubuntu-os-cloud/ubuntu-2204-lts-arm64"},
+						},
+					},
+				},
+			},
+			expectARM64: false,
+			expectArch:  "X86_64",
+			expectNotes: "Misconfigured: x86_64 machine type e2-standard-2 boots arm64 image",
+		},
+		{
+			name:         "GPU instance",
+			resourceType: "google_compute_instance",
+			attributes: map[string]interface{}{
+				"machine_type":      "n1-standard-8",
+				"guest_accelerator": []any{map[string]any{"type": "nvidia-tesla-t4", "count": float64(1)}},
+			},
+			expectARM64: false,
+			expectArch:  "X86_64",
+			expectNotes: "Blocked: Arm machine types do not support guest_accelerator",
+		},
+		{
+			name:         "Custom machine type",
+			resourceType: "google_compute_instance",
+			attributes: map[string]interface{}{
+				"machine_type": "n2-custom-4-16384",
+			},
+			expectARM64: false,
+			expectArch:  "X86_64",
+			expectNotes: "No Arm machine type available for n2-custom-4-16384",
+		},
+		{
+			name:         "Windows instance template",
+			resourceType: "google_compute_instance_template",
+			attributes: map[string]interface{}{
+				"machine_type": "n2-standard-4",
+				"disk": []any{
+					map[string]any{"boot": true, "source_image": "windows-cloud/windows-2022"},
+				},
+			},
+			expectNA:    true,
+			expectArch:  "X86_64",
+			expectNotes: "Not applicable: Windows",
+		},
+		{
+			name:         "Shared-core instance template",
+			resourceType: "google_compute_instance_template",
+			attributes: map[string]interface{}{
+				"machine_type": "e2-medium",
+				"disk": []any{
+					map[string]any{"boot": true, "source_image": "cos-cloud/cos-stable"},
+				},
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "t2a-standard-1",
+			expectNotes:     "in place of cos-cloud/cos-stable",
+		},
+		{
+			name:         "GKE node pool",
+			resourceType: "google_container_node_pool",
+			attributes: map[string]interface{}{
+				"node_config": []any{
+					map[string]any{"machine_type": "n2d-highmem-8", "image_type": "COS_CONTAINERD"},
+				},
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "c4a-highmem-8",
+			expectNotes:     "kubernetes.io/arch=arm64:NoSchedule",
+		},
+		{
+			name:            "GKE node pool with default machine type",
+			resourceType:    "google_container_node_pool",
+			attributes:      map[string]interface{}{},
+			expectARM64:     true,
+			expectArch:      "X86_64 (default)",
+			expectRecommend: "t2a-standard-1",
+		},
+		{
+			name:         "GKE node pool on legacy Docker image",
+			resourceType: "google_container_node_pool",
+			attributes: map[string]interface{}{
+				"node_config": []any{
+					map[string]any{"machine_type": "e2-standard-4", "image_type": "UBUNTU"},
+				},
+			},
+			expectARM64:     true,
+			expectArch:      "X86_64",
+			expectRecommend: "c4a-standard-4",
+			expectNotes:     "Prerequisite: switch image_type UBUNTU to COS_CONTAINERD",
+		},
+		{
+			name:         "GKE Windows node pool",
+			resourceType: "google_container_node_pool",
+			attributes: map[string]interface{}{
+				"node_config": []any{
+					map[string]any{"machine_type": "n2-standard-4", "image_type": "WINDOWS_LTSC_CONTAINERD"},
+				},
+			},
+			expectNA:    true,
+			expectArch:  "X86_64",
+			expectNotes: "Not applicable: Windows",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := parser.TerraformResource{
+				Mode:      "managed",
+				Type:      tt.resourceType,
+				Name:      "test",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			}
+
+			analysis := AnalyzeResource(resource)
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("ARM64Compatible = %v, want %v (notes: %s)", analysis.ARM64Compatible, tt.expectARM64, analysis.Notes)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectAlready {
+				t.Errorf("AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectAlready)
+			}
+			if analysis.NotApplicable != tt.expectNA {
+				t.Errorf("NotApplicable = %v, want %v", analysis.NotApplicable, tt.expectNA)
+			}
+			if analysis.CurrentArch != tt.expectArch {
+				t.Errorf("CurrentArch = %v, want %v", analysis.CurrentArch, tt.expectArch)
+			}
+			if tt.expectRecommend != "" && analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
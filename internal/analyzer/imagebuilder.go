@@ -0,0 +1,281 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type ImageRecipeAnalyzer struct {
+	ctx *Context
+}
+
+func (a *ImageRecipeAnalyzer) SupportedType() string {
+	return "aws_imagebuilder_image_recipe"
+}
+
+func (a *ImageRecipeAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		parentImage, _ := instance.Attributes["parent_image"].(string)
+		if platform, _ := instance.Attributes["platform"].(string); isWindowsOperatingSystem(platform) || strings.Contains(parentImage, "windows") {
+			markNotApplicableWindows(&analysis, parentImage)
+			continue
+		}
+
+		name, _ := instance.Attributes["name"].(string)
+		switch getImageBuilderParentArch(parentImage) {
+		case ArchitectureARM64:
+			analysis.setArchitecture(ArchitectureARM64)
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "parent_image", "Already building an arm64 golden image")
+		case ArchitectureX86_64:
+			analysis.setArchitecture(ArchitectureX86_64)
+			analysis.RecommendedArch = getImageBuilderARM64Parent(parentImage)
+			if variant := a.ctx.findARM64RecipeVariant(name); variant != "" {
+				analysis.decide(StatusMigratable, FindingMigratable, "parent_image", "arm64 variant of this golden image is built by "+variant)
+			} else {
+				analysis.decide(StatusMigratable, FindingMigratable, "parent_image", "No arm64 variant of this golden image is built")
+				analysis.addFinding(FindingNote, "parent_image", "Add a recipe with parent_image "+analysis.RecommendedArch+" and Graviton instance types in its infrastructure configuration")
+			}
+		default:
+			analysis.RecommendedArch = "ARM64"
+			markUnknown(&analysis, "parent_image", "Architecture of parent_image "+parentImage+" unknown; verify an arm64 parent image is available")
+		}
+	}
+	return analysis
+}
+
+// findARM64RecipeVariant returns the address of an arm64 image recipe that
+// builds the same golden image as the named recipe, judged by their names
+// without architecture tokens.
+func (c *Context) findARM64RecipeVariant(name string) string {
+	goldenImage := getGoldenImageName(name)
+	for _, recipe := range c.FindResources("aws_imagebuilder_image_recipe") {
+		for _, instance := range recipe.Instances {
+			recipeName, _ := instance.Attributes["name"].(string)
+			parentImage, _ := instance.Attributes["parent_image"].(string)
+			if getGoldenImageName(recipeName) == goldenImage && getImageBuilderParentArch(parentImage) == ArchitectureARM64 {
+				return recipe.GetFullAddress()
+			}
+		}
+	}
+	return ""
+}
+
+type InfrastructureConfigurationAnalyzer struct{}
+
+func (a *InfrastructureConfigurationAnalyzer) SupportedType() string {
+	return "aws_imagebuilder_infrastructure_configuration"
+}
+
+func (a *InfrastructureConfigurationAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		instanceTypes := getStringList(instance.Attributes["instance_types"])
+		switch getInstanceTypesArch(instanceTypes) {
+		case "":
+			// The pipelines using the configuration decide, and are counted,
+			// on their own
+			analysis.setArchitecture(ArchitectureAny)
+			analysis.decide(StatusUnknown, FindingUnverified, "instance_types", "No instance_types set; Image Builder picks build instances matching the recipe's architecture")
+		case ArchitectureARM64:
+			analysis.setArchitecture(ArchitectureARM64)
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "instance_types", "Already building on ARM64 instance types")
+		case ArchitectureMixed:
+			analysis.setArchitecture(ArchitectureMixed)
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusPartiallyMigrated, FindingPartiallyMigrated, "instance_types", "instance_types mixes arm64 and x86_64; only types matching each recipe's architecture can build it")
+		default:
+			analysis.setArchitecture(ArchitectureX86_64)
+			var alternatives []string
+			for _, instanceType := range instanceTypes {
+				if alternative := getARM64Alternative(instanceType); alternative != "" {
+					alternatives = append(alternatives, alternative)
+				}
+			}
+			if len(alternatives) == 0 {
+				analysis.decide(StatusBlocked, FindingNoARM64Option, "instance_types", "No ARM64 compatible instance type available for "+strings.Join(instanceTypes, ", "))
+				continue
+			}
+			analysis.RecommendedArch = strings.Join(alternatives, ", ")
+			analysis.decide(StatusMigratable, FindingMigratable, "instance_types", "arm64 golden images need an infrastructure configuration with instance_types "+analysis.RecommendedArch)
+		}
+	}
+	return analysis
+}
+
+type ImagePipelineAnalyzer struct {
+	ctx *Context
+}
+
+func (a *ImagePipelineAnalyzer) SupportedType() string {
+	return "aws_imagebuilder_image_pipeline"
+}
+
+// Analyze checks that a pipeline's recipe and infrastructure configuration
+// agree on the architecture of the golden image it builds.
+func (a *ImagePipelineAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		recipeARN, _ := instance.Attributes["image_recipe_arn"].(string)
+		if recipeARN == "" {
+			analysis.setArchitecture(ArchitectureUnknown)
+			analysis.decide(StatusUnknown, FindingUnverified, "container_recipe_arn", "Builds a container image; the container recipe's parent_image was not checked")
+			continue
+		}
+
+		recipe, found := a.ctx.findResourceByARN("aws_imagebuilder_image_recipe", recipeARN)
+		if !found {
+			analysis.RecommendedArch = "ARM64"
+			markUnknown(&analysis, "image_recipe_arn", "Image recipe "+recipeARN+" not found in state")
+			continue
+		}
+		recipeAnalysis := (&ImageRecipeAnalyzer{ctx: a.ctx}).Analyze(recipe)
+		analysis.adoptVerdict(recipeAnalysis, "image_recipe_arn")
+		analysis.addFinding(FindingNote, "image_recipe_arn", "Recipe "+recipe.GetFullAddress())
+
+		infrastructureARN, _ := instance.Attributes["infrastructure_configuration_arn"].(string)
+		infrastructure, found := a.ctx.findResourceByARN("aws_imagebuilder_infrastructure_configuration", infrastructureARN)
+		if !found || recipeAnalysis.Status == StatusNotApplicable || recipeAnalysis.Architecture == "" {
+			continue
+		}
+		infrastructureArch := getInstanceTypesArch(getStringList(infrastructure.Instances[0].Attributes["instance_types"]))
+		if infrastructureArch != "" && infrastructureArch != ArchitectureMixed && infrastructureArch != recipeAnalysis.Architecture {
+			analysis.setArchitecture(ArchitectureMixed)
+			analysis.RecommendedArch = ""
+			analysis.decide(StatusMisconfigured, FindingMisconfigured, "infrastructure_configuration_arn", recipeAnalysis.CurrentArch+" recipe "+recipe.GetFullAddress()+
+				" builds on "+getLegacyArchitectures()[infrastructureArch]+" instance types of "+infrastructure.GetFullAddress())
+		}
+	}
+	return analysis
+}
+
+// findResourceByARN finds the instance of the given type with the given ARN.
+func (c *Context) findResourceByARN(resourceType, arn string) (parser.TerraformResource, bool) {
+	if arn == "" {
+		return parser.TerraformResource{}, false
+	}
+	for _, resource := range c.FindResources(resourceType) {
+		for _, instance := range resource.Instances {
+			if resourceARN, _ := instance.Attributes["arn"].(string); resourceARN == arn {
+				resource.Instances = []parser.ResourceInstance{instance}
+				return resource, true
+			}
+		}
+	}
+	return parser.TerraformResource{}, false
+}
+
+// describeGoldenImage reports whether an arm64 variant of the Image Builder
+// golden image that produced an AMI is built, as a finding about the
+// attribute holding the AMI. AMIs not built by Image Builder in this state
+// are reported as unverified, since nothing shows an arm64 variant exists.
+// It reports false when there is no AMI.
+func (c *Context) describeGoldenImage(ami, attribute string) (Finding, bool) {
+	if ami == "" {
+		return Finding{}, false
+	}
+	for _, image := range c.FindResources("aws_imagebuilder_image") {
+		for _, instance := range image.Instances {
+			if !hasOutputAMI(instance.Attributes, ami) {
+				continue
+			}
+			recipeARN, _ := instance.Attributes["image_recipe_arn"].(string)
+			recipe, found := c.findResourceByARN("aws_imagebuilder_image_recipe", recipeARN)
+			if !found {
+				return newFinding(FindingUnverified, attribute, "AMI "+ami+" is built by "+image.GetFullAddress()+"; verify an arm64 variant is built"), true
+			}
+			name, _ := recipe.Instances[0].Attributes["name"].(string)
+			if variant := c.findARM64RecipeVariant(name); variant != "" {
+				return newFinding(FindingNote, attribute, "Use the arm64 golden image built by "+variant), true
+			}
+			return newFinding(FindingPrerequisite, attribute, "build an arm64 variant of golden image "+recipe.GetFullAddress()), true
+		}
+	}
+	return newFinding(FindingUnverified, attribute, "Requires an arm64 AMI in place of "+ami), true
+}
+
+// hasOutputAMI reports whether an aws_imagebuilder_image produced the AMI.
+func hasOutputAMI(attributes map[string]any, ami string) bool {
+	outputs, _ := attributes["output_resources"].([]any)
+	for _, output := range outputs {
+		outputMap, ok := output.(map[string]any)
+		if !ok {
+			continue
+		}
+		amis, _ := outputMap["amis"].([]any)
+		for _, image := range amis {
+			if imageMap, ok := image.(map[string]any); ok && imageMap["image"] == ami {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getInstanceTypesArch returns the architecture shared by a list of instance
+// types, mixed when they differ, or an empty string for an empty list.
+func getInstanceTypesArch(instanceTypes []string) Architecture {
+	var arch Architecture
+	for _, instanceType := range instanceTypes {
+		typeArch := getArchFromInstanceType(instanceType)
+		switch {
+		case arch == "":
+			arch = typeArch
+		case arch != typeArch:
+			return ArchitectureMixed
+		}
+	}
+	return arch
+}
+
+// getImageBuilderParentArch infers the architecture of a parent image from
+// the AWS managed image naming scheme, e.g.
+// "arn:aws:imagebuilder:us-east-1:aws:image/amazon-linux-2023-arm64/x.x.x".
+// AMI IDs and custom images return an empty string.
+func getImageBuilderParentArch(parentImage string) Architecture {
+	parentImage = strings.ToLower(parentImage)
+	switch {
+	case strings.Contains(parentImage, "arm64"), strings.Contains(parentImage, "aarch64"):
+		return ArchitectureARM64
+	case strings.Contains(parentImage, "x86"), strings.Contains(parentImage, "amd64"):
+		return ArchitectureX86_64
+	default:
+		return ""
+	}
+}
+
+// getImageBuilderARM64Parent returns the arm64 managed image matching an
+// x86_64 one.
+func getImageBuilderARM64Parent(parentImage string) string {
+	parentImage = strings.Replace(parentImage, "x86_64", "arm64", 1)
+	parentImage = strings.Replace(parentImage, "amd64", "arm64", 1)
+	return strings.Replace(parentImage, "x86", "arm64", 1)
+}
+
+// getGoldenImageName strips architecture tokens from a recipe name so that
+// per-architecture recipes of one golden image compare equal.
+func getGoldenImageName(name string) string {
+	name = strings.ToLower(name)
+	for _, token := range []string{"x86_64", "x86-64", "x86", "amd64", "arm64", "aarch64", "graviton"} {
+		name = strings.ReplaceAll(name, token, "")
+	}
+	return strings.Trim(strings.NewReplacer("--", "-", "__", "_").Replace(name), "-_")
+}
@@ -0,0 +1,162 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func newImageBuilderTestState(withARM64Variant bool) *parser.TerraformState {
+	resource := func(resourceType, name string, attributes map[string]interface{}) parser.TerraformResource {
+		return parser.TerraformResource{
+			Mode:      "managed",
+			Type:      resourceType,
+			Name:      name,
+			Instances: []parser.ResourceInstance{{Attributes: attributes}},
+		}
+	}
+
+	resources := []parser.TerraformResource{
+		resource("aws_imagebuilder_image_recipe", "base_x86", map[string]interface{}{
+			"arn":          "arn:aws:imagebuilder:us-east-1:123456789012:image-recipe/base-x86/1.0.0",
+			"name":         "base-x86",
+			"parent_image": "arn:aws:imagebuilder:us-east-1:aws:image/amazon-linux-2023-x86/x.x.x",
+			"platform":     "Linux",
+		}),
+		resource("aws_imagebuilder_infrastructure_configuration", "arm", map[string]interface{}{
+			"arn":            "arn:aws:imagebuilder:us-east-1:123456789012:infrastructure-configuration/arm",
+			"instance_types": []any{"c7g.large"},
+		}),
+		resource("aws_imagebuilder_image", "base_x86", map[string]interface{}{
+			"image_recipe_arn": "arn:aws:imagebuilder:us-east-1:123456789012:image-recipe/base-x86/1.0.0",
+			"output_resources": []any{
+				map[string]any{"amis": []any{map[string]any{"image": "ami-0123456789abcdef0"}}},
+			},
+		}),
+	}
+	if withARM64Variant {
+		resources = append(resources, resource("aws_imagebuilder_image_recipe", "base_arm64", map[string]interface{}{
+			"name":         "base-arm64",
+			"parent_image": "arn:aws:imagebuilder:us-east-1:aws:image/amazon-linux-2023-arm64/x.x.x",
+			"platform":     "Linux",
+		}))
+	}
+	return &parser.TerraformState{Version: 4, Resources: resources}
+}
+
+func TestImageRecipeAnalyzer_Analyze(t *testing.T) {
+	recipe := newImageBuilderTestState(false).Resources[0]
+
+	analysis := (&ImageRecipeAnalyzer{ctx: NewContext(newImageBuilderTestState(false))}).Analyze(recipe)
+	if !analysis.ARM64Compatible || analysis.RecommendedArch != "arn:aws:imagebuilder:us-east-1:aws:image/amazon-linux-2023-arm64/x.x.x" {
+		t.Errorf("Analyze() = %v %q, want the arm64 parent image", analysis.ARM64Compatible, analysis.RecommendedArch)
+	}
+	if !strings.Contains(analysis.Notes, "No arm64 variant of this golden image is built") {
+		t.Errorf("Analyze() Notes = %q, want a missing variant", analysis.Notes)
+	}
+
+	analysis = (&ImageRecipeAnalyzer{ctx: NewContext(newImageBuilderTestState(true))}).Analyze(recipe)
+	if !strings.Contains(analysis.Notes, "arm64 variant of this golden image is built by aws_imagebuilder_image_recipe.base_arm64") {
+		t.Errorf("Analyze() Notes = %q, want the arm64 variant", analysis.Notes)
+	}
+}
+
+func TestImagePipelineAnalyzer_ArchitectureMismatch(t *testing.T) {
+	analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+		Type: "aws_imagebuilder_image_pipeline",
+		Name: "base",
+		Instances: []parser.ResourceInstance{
+			{
+				Attributes: map[string]interface{}{
+					"image_recipe_arn":                 "arn:aws:imagebuilder:us-east-1:123456789012:image-recipe/base-x86/1.0.0",
+					"infrastructure_configuration_arn": "arn:aws:imagebuilder:us-east-1:123456789012:infrastructure-configuration/arm",
+				},
+			},
+		},
+	}, NewContext(newImageBuilderTestState(false)))
+
+	if analysis.ARM64Compatible {
+		t.Errorf("Analyze() ARM64Compatible = true, want false")
+	}
+	want := "Misconfigured: X86_64 recipe aws_imagebuilder_image_recipe.base_x86 builds on ARM64 instance types of aws_imagebuilder_infrastructure_configuration.arm"
+	if !strings.Contains(analysis.Notes, want) {
+		t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, want)
+	}
+}
+
+func TestImageBuilderAnalyzers_Unverified(t *testing.T) {
+	tests := []struct {
+		name         string
+		resourceType string
+		attributes   map[string]interface{}
+		expectNotes  string
+	}{
+		{
+			name:         "infrastructure configuration without instance types",
+			resourceType: "aws_imagebuilder_infrastructure_configuration",
+			attributes:   map[string]interface{}{"instance_profile_name": "builder"},
+			expectNotes:  "No instance_types set",
+		},
+		{
+			name:         "container pipeline",
+			resourceType: "aws_imagebuilder_image_pipeline",
+			attributes:   map[string]interface{}{"container_recipe_arn": "arn:aws:imagebuilder:us-east-1:123456789012:container-recipe/app/1.0.0"},
+			expectNotes:  "Builds a container image; the container recipe's parent_image was not checked",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Type:      tt.resourceType,
+				Name:      "base",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			}, NewContext(newImageBuilderTestState(false)))
+
+			if analysis.Status != StatusUnknown || analysis.ARM64Compatible {
+				t.Errorf("Analyze() Status = %v, ARM64Compatible = %v, want unknown", analysis.Status, analysis.ARM64Compatible)
+			}
+			if analysis.EstimatedSavingsPercent != 0 {
+				t.Errorf("Analyze() EstimatedSavingsPercent = %v, want no savings", analysis.EstimatedSavingsPercent)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
+
+func TestEC2Analyzer_GoldenImagePrerequisite(t *testing.T) {
+	tests := []struct {
+		name             string
+		withARM64Variant bool
+		expectNotes      string
+	}{
+		{
+			name:        "no arm64 variant built",
+			expectNotes: "Prerequisite: build an arm64 variant of golden image aws_imagebuilder_image_recipe.base_x86",
+		},
+		{
+			name:             "arm64 variant built",
+			withARM64Variant: true,
+			expectNotes:      "Use the arm64 golden image built by aws_imagebuilder_image_recipe.base_arm64",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Type: "aws_instance",
+				Name: "web",
+				Instances: []parser.ResourceInstance{
+					{Attributes: map[string]interface{}{"instance_type": "m5.large", "ami": "ami-0123456789abcdef0"}},
+				},
+			}, NewContext(newImageBuilderTestState(tt.withARM64Variant)))
+
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
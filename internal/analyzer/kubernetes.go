@@ -0,0 +1,387 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+// KubernetesWorkloadAnalyzer analyzes workloads managed by the kubernetes
+// provider's typed resources, such as kubernetes_deployment.
+type KubernetesWorkloadAnalyzer struct {
+	ctx *Context
+}
+
+func (a *KubernetesWorkloadAnalyzer) SupportedType() string {
+	return "kubernetes_deployment"
+}
+
+func (a *KubernetesWorkloadAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		var scheduling podScheduling
+		scheduling.collect(instance.Attributes["spec"], "spec")
+		applyPodScheduling(&analysis, a.ctx, scheduling)
+	}
+	return analysis
+}
+
+// KubernetesManifestAnalyzer analyzes kubernetes_manifest resources: pod
+// controllers by their pod template and Karpenter node pools by their
+// requirements.
+type KubernetesManifestAnalyzer struct {
+	ctx *Context
+}
+
+func (a *KubernetesManifestAnalyzer) SupportedType() string {
+	return "kubernetes_manifest"
+}
+
+func (a *KubernetesManifestAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		manifest := getKubernetesManifest(instance.Attributes)
+		kind, _ := manifest["kind"].(string)
+
+		var scheduling podScheduling
+		scheduling.collect(manifest["spec"], "spec")
+		switch kind {
+		case "NodePool", "Provisioner":
+			applyKarpenterRequirements(&analysis, kind, scheduling)
+		case "Deployment", "DaemonSet", "StatefulSet", "ReplicaSet", "Job", "CronJob", "Pod":
+			applyPodScheduling(&analysis, a.ctx, scheduling)
+		default:
+			analysis.decide(StatusNotApplicable, FindingNotApplicable, "manifest", kind+" does not schedule pods")
+		}
+	}
+	return analysis
+}
+
+type HelmReleaseAnalyzer struct {
+	ctx *Context
+}
+
+func (a *HelmReleaseAnalyzer) SupportedType() string {
+	return "helm_release"
+}
+
+func (a *HelmReleaseAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+
+	for _, instance := range resource.Instances {
+		var scheduling podScheduling
+		found := false
+
+		// The helm provider records the user-supplied values as JSON in
+		// metadata; values documents are YAML, which is only readable here
+		// when written with jsonencode
+		documents := getStringList(instance.Attributes["values"])
+		if metadata := getFirstBlock(instance.Attributes["metadata"]); metadata != nil {
+			if values, ok := metadata["values"].(string); ok && values != "" {
+				documents = []string{values}
+			}
+		}
+		for _, document := range documents {
+			var values any
+			if err := json.Unmarshal([]byte(document), &values); err == nil {
+				scheduling.collect(values, "values")
+				found = true
+			}
+		}
+
+		for _, attribute := range []string{"set", "set_list"} {
+			settings, _ := instance.Attributes[attribute].([]any)
+			for _, setting := range settings {
+				settingMap, ok := setting.(map[string]any)
+				if !ok {
+					continue
+				}
+				name, _ := settingMap["name"].(string)
+				value, _ := settingMap["value"].(string)
+				scheduling.collectHelmSetting(name, value)
+				found = true
+			}
+		}
+
+		if !found {
+			analysis.RecommendedArch = "ARM64"
+			markUnknown(&analysis, "values", "No chart values in state; verify the chart's images support arm64 and it does not pin kubernetes.io/arch")
+			continue
+		}
+		applyPodScheduling(&analysis, a.ctx, scheduling)
+	}
+	return analysis
+}
+
+// archRequirement is one constraint on the kubernetes.io/arch node label.
+type archRequirement struct {
+	// Source is the attribute path the constraint was found at
+	Source string
+	// Allowed lists the architectures the constraint admits
+	Allowed []string
+}
+
+// podScheduling collects the architecture constraints and images found in a
+// pod spec, Helm values or Karpenter requirements.
+type podScheduling struct {
+	Required         []archRequirement
+	Preferred        []archRequirement
+	Images           []string
+	InstanceFamilies []string
+}
+
+// collect walks nested attributes. The kubernetes provider's typed resources
+// use snake_case attribute names while manifests and Helm values use the
+// Kubernetes API's camelCase, so both spellings are recognised.
+func (s *podScheduling) collect(node any, path string) {
+	switch value := node.(type) {
+	case map[string]any:
+		if slices.Contains([]string{"matchExpressions", "match_expressions", "requirements"}, getLastSegment(path)) {
+			s.collectExpression(value, path)
+			return
+		}
+		// Walk keys in order so notes are stable across runs
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			child := value[key]
+			childPath := path + "." + key
+			switch key {
+			case "nodeSelector", "node_selector":
+				if selector, ok := child.(map[string]any); ok {
+					for _, label := range slices.Sorted(maps.Keys(selector)) {
+						if arch, ok := selector[label].(string); ok && isArchLabel(label) {
+							s.Required = append(s.Required, archRequirement{Source: childPath, Allowed: []string{arch}})
+						}
+					}
+				}
+			case "image":
+				s.collectImage(child)
+			default:
+				s.collect(child, childPath)
+			}
+		}
+	case []any:
+		for _, child := range value {
+			s.collect(child, path)
+		}
+	}
+}
+
+// collectExpression records a node selector expression or Karpenter
+// requirement on the architecture or instance family labels.
+func (s *podScheduling) collectExpression(expression map[string]any, path string) {
+	key, _ := expression["key"].(string)
+	operator, _ := expression["operator"].(string)
+	values := getStringList(expression["values"])
+
+	switch {
+	case isArchLabel(key):
+		requirement := archRequirement{Source: path, Allowed: getAllowedArchs(operator, values)}
+		if strings.Contains(strings.ToLower(path), "preferred") {
+			s.Preferred = append(s.Preferred, requirement)
+		} else {
+			s.Required = append(s.Required, requirement)
+		}
+	case key == "karpenter.k8s.aws/instance-family" && operator == "In":
+		s.InstanceFamilies = append(s.InstanceFamilies, values...)
+	}
+}
+
+// collectImage records a container image, either a plain reference or the
+// repository/tag map used by most Helm charts.
+func (s *podScheduling) collectImage(value any) {
+	switch image := value.(type) {
+	case string:
+		if image != "" {
+			s.Images = append(s.Images, image)
+		}
+	case map[string]any:
+		repository, _ := image["repository"].(string)
+		if repository == "" {
+			return
+		}
+		if registry, ok := image["registry"].(string); ok && registry != "" {
+			repository = registry + "/" + repository
+		}
+		if tag, ok := image["tag"].(string); ok && tag != "" {
+			repository += ":" + tag
+		}
+		s.Images = append(s.Images, repository)
+	}
+}
+
+// collectHelmSetting records a --set style value, whose name escapes the
+// dots inside label keys, e.g. "nodeSelector.kubernetes\.io/arch".
+func (s *podScheduling) collectHelmSetting(name, value string) {
+	unescaped := strings.ReplaceAll(name, `\.`, ".")
+	switch {
+	case strings.HasSuffix(unescaped, "kubernetes.io/arch") && value != "":
+		s.Required = append(s.Required, archRequirement{Source: "set " + name, Allowed: []string{value}})
+	case (unescaped == "image" || strings.HasSuffix(unescaped, ".image") || strings.HasSuffix(unescaped, "image.repository")) && value != "":
+		s.Images = append(s.Images, value)
+	}
+}
+
+// allowedArchs intersects the required constraints; an unconstrained
+// workload may run on either architecture.
+func (s *podScheduling) allowedArchs() []string {
+	allowed := []string{"amd64", "arm64"}
+	for _, requirement := range s.Required {
+		allowed = slices.DeleteFunc(allowed, func(arch string) bool { return !slices.Contains(requirement.Allowed, arch) })
+	}
+	return allowed
+}
+
+func (s *podScheduling) sources(requirements []archRequirement) string {
+	var sources []string
+	for _, requirement := range requirements {
+		sources = append(sources, requirement.Source+" ("+strings.Join(requirement.Allowed, ", ")+")")
+	}
+	return strings.Join(sources, ", ")
+}
+
+// applyPodScheduling records whether a workload blocks moving its nodes to
+// Graviton: either it pins kubernetes.io/arch to amd64 or its images have
+// no arm64 variant.
+func applyPodScheduling(analysis *ARM64Analysis, ctx *Context, scheduling podScheduling) {
+	var results, statuses, blocked []string
+	for _, image := range scheduling.Images {
+		status := ctx.checkContainerImage(image)
+		results = append(results, image+": "+status)
+		statuses = append(statuses, status)
+		if status == containerImageX86Only {
+			blocked = append(blocked, image)
+		}
+	}
+
+	allowed := scheduling.allowedArchs()
+	switch {
+	case len(allowed) == 0:
+		analysis.setArchitecture(ArchitectureMixed)
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, scheduling.Required[0].Source, "kubernetes.io/arch constraints admit no architecture: "+scheduling.sources(scheduling.Required))
+	case slices.Equal(allowed, []string{"arm64"}):
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.RecommendedArch = "ARM64"
+		if len(blocked) > 0 {
+			analysis.decide(StatusMisconfigured, FindingMisconfigured, "", "Scheduled on arm64 nodes but images lack an arm64 variant: "+strings.Join(blocked, ", "))
+		} else {
+			analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "", "Already scheduled on arm64 nodes")
+		}
+	case len(blocked) > 0:
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.decide(StatusBlocked, FindingBlocked, "", "Blocks Graviton nodes: images without an arm64 variant: "+strings.Join(blocked, ", "))
+	case slices.Equal(allowed, []string{"amd64"}):
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusMigratable, FindingMigratable, scheduling.Required[0].Source, "Pinned to amd64 by "+scheduling.sources(scheduling.Required))
+		analysis.addFinding(FindingNote, "", "Remove the pin or allow arm64 before moving nodes to Graviton")
+	default:
+		analysis.setArchitecture(ArchitectureAny)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusMigratable, FindingMigratable, "", "Not pinned to an architecture; can schedule on Graviton nodes")
+	}
+
+	if len(scheduling.Preferred) > 0 {
+		analysis.addFinding(FindingNote, "", "Preferred affinity: "+scheduling.sources(scheduling.Preferred))
+	}
+	if len(results) > 0 {
+		analysis.addFinding(getImageFindingCode(statuses), "", "Images: "+strings.Join(results, "; "))
+	}
+}
+
+// applyKarpenterRequirements records which architectures a Karpenter
+// NodePool or Provisioner can launch.
+func applyKarpenterRequirements(analysis *ARM64Analysis, kind string, scheduling podScheduling) {
+	allowed := scheduling.allowedArchs()
+	switch {
+	case len(scheduling.Required) == 0:
+		analysis.setDefaultArchitecture(ArchitectureX86_64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusMigratable, FindingMigratable, "", "No kubernetes.io/arch requirement; Karpenter defaults to amd64")
+		analysis.addFinding(FindingNote, "", "Add a requirement with values [\"arm64\", \"amd64\"]")
+	case len(allowed) == 0:
+		analysis.setArchitecture(ArchitectureMixed)
+		analysis.decide(StatusMisconfigured, FindingMisconfigured, scheduling.Required[0].Source, "kubernetes.io/arch requirements admit no architecture: "+scheduling.sources(scheduling.Required))
+	case slices.Equal(allowed, []string{"arm64"}):
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "", kind+" only launches arm64 nodes")
+	case slices.Equal(allowed, []string{"amd64"}):
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusMigratable, FindingMigratable, scheduling.Required[0].Source, kind+" restricts kubernetes.io/arch to amd64 by "+scheduling.sources(scheduling.Required))
+		analysis.addFinding(FindingNote, "", "Add arm64 to the requirement values")
+	default:
+		analysis.setArchitecture(ArchitectureMixed)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusPartiallyMigrated, FindingPartiallyMigrated, "", kind+" can launch arm64 and amd64 nodes; Karpenter picks the cheapest that fits")
+	}
+
+	var alternatives []string
+	for _, family := range scheduling.InstanceFamilies {
+		if alternative := getARM64InstanceFamilyAlternative(family); alternative != "" && !slices.Contains(scheduling.InstanceFamilies, alternative) {
+			alternatives = append(alternatives, alternative)
+		}
+	}
+	if len(alternatives) > 0 && analysis.canMigrate() {
+		analysis.addFinding(FindingNote, "", fmt.Sprintf("karpenter.k8s.aws/instance-family also needs Graviton families: %s", strings.Join(alternatives, ", ")))
+	}
+}
+
+// getKubernetesManifest returns the object of a kubernetes_manifest. The
+// attribute has a dynamic type, which state stores wrapped as
+// {"value": ..., "type": ...}; object includes server-side defaults.
+func getKubernetesManifest(attributes map[string]any) map[string]any {
+	for _, attribute := range []string{"object", "manifest"} {
+		value, ok := attributes[attribute].(map[string]any)
+		if !ok {
+			continue
+		}
+		if wrapped, ok := value["value"].(map[string]any); ok && value["type"] != nil {
+			value = wrapped
+		}
+		if len(value) > 0 {
+			return value
+		}
+	}
+	return nil
+}
+
+// getAllowedArchs returns the architectures a node selector operator admits.
+func getAllowedArchs(operator string, values []string) []string {
+	switch operator {
+	case "In":
+		return values
+	case "NotIn":
+		return slices.DeleteFunc([]string{"amd64", "arm64"}, func(arch string) bool { return slices.Contains(values, arch) })
+	case "DoesNotExist":
+		return nil
+	default:
+		return []string{"amd64", "arm64"}
+	}
+}
+
+func isArchLabel(label string) bool {
+	return label == "kubernetes.io/arch" || label == "beta.kubernetes.io/arch"
+}
+
+func getLastSegment(path string) string {
+	return path[strings.LastIndex(path, ".")+1:]
+}
@@ -0,0 +1,199 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestKubernetesWorkloadAnalyzer_Analyze(t *testing.T) {
+	podSpec := func(extra map[string]any) []any {
+		spec := map[string]any{
+			"container": []any{map[string]any{"name": "app", "image": "nginx:1.27"}},
+		}
+		for key, value := range extra {
+			spec[key] = value
+		}
+		return []any{map[string]any{"template": []any{map[string]any{"spec": []any{spec}}}}}
+	}
+
+	tests := []struct {
+		name        string
+		spec        []any
+		expectARM64 bool
+		expectUsing bool
+		expectNotes string
+	}{
+		{
+			name:        "unpinned workload",
+			spec:        podSpec(nil),
+			expectARM64: true,
+			expectNotes: "Not pinned to an architecture",
+		},
+		{
+			name:        "node selector pins amd64",
+			spec:        podSpec(map[string]any{"node_selector": map[string]any{"kubernetes.io/arch": "amd64"}}),
+			expectARM64: true,
+			expectNotes: "Pinned to amd64 by spec.template.spec.node_selector (amd64)",
+		},
+		{
+			name: "required affinity excludes arm64",
+			spec: podSpec(map[string]any{
+				"affinity": []any{map[string]any{
+					"node_affinity": []any{map[string]any{
+						"required_during_scheduling_ignored_during_execution": []any{map[string]any{
+							"node_selector_term": []any{map[string]any{
+								"match_expressions": []any{map[string]any{
+									"key": "kubernetes.io/arch", "operator": "NotIn", "values": []any{"arm64"},
+								}},
+							}},
+						}},
+					}},
+				}},
+			}),
+			expectARM64: true,
+			expectNotes: "Pinned to amd64",
+		},
+		{
+			name:        "arm64 node selector",
+			spec:        podSpec(map[string]any{"node_selector": map[string]any{"kubernetes.io/arch": "arm64"}}),
+			expectARM64: true,
+			expectUsing: true,
+			expectNotes: "Already scheduled on arm64 nodes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Type:      "kubernetes_deployment",
+				Name:      "app",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]interface{}{"spec": tt.spec}}},
+			}, nil)
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
+
+func TestKubernetesManifestAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name         string
+		manifest     map[string]any
+		expectARM64  bool
+		expectUsing  bool
+		expectNotApp bool
+		expectNotes  string
+	}{
+		{
+			name: "Karpenter NodePool restricted to amd64",
+			manifest: map[string]any{
+				"kind": "NodePool",
+				"spec": map[string]any{"template": map[string]any{"spec": map[string]any{
+					"requirements": []any{
+						map[string]any{"key": "kubernetes.io/arch", "operator": "In", "values": []any{"amd64"}},
+						map[string]any{"key": "karpenter.k8s.aws/instance-family", "operator": "In", "values": []any{"m5", "c5"}},
+					},
+				}}},
+			},
+			expectARM64: true,
+			expectNotes: "NodePool restricts kubernetes.io/arch to amd64 by spec.template.spec.requirements (amd64) | Add arm64 to the requirement values | karpenter.k8s.aws/instance-family also needs Graviton families: m7g, c7g",
+		},
+		{
+			name: "Karpenter Provisioner launching both architectures",
+			manifest: map[string]any{
+				"kind": "Provisioner",
+				"spec": map[string]any{"requirements": []any{
+					map[string]any{"key": "kubernetes.io/arch", "operator": "In", "values": []any{"amd64", "arm64"}},
+				}},
+			},
+			expectARM64: true,
+			expectNotes: "Provisioner can launch arm64 and amd64 nodes",
+		},
+		{
+			name: "Deployment with preferred arm64 affinity",
+			manifest: map[string]any{
+				"kind": "Deployment",
+				"spec": map[string]any{"template": map[string]any{"spec": map[string]any{
+					"containers": []any{map[string]any{"image": "nginx:1.27"}},
+					"affinity": map[string]any{"nodeAffinity": map[string]any{
+						"preferredDuringSchedulingIgnoredDuringExecution": []any{map[string]any{
+							"preference": map[string]any{"matchExpressions": []any{
+								map[string]any{"key": "kubernetes.io/arch", "operator": "In", "values": []any{"arm64"}},
+							}},
+						}},
+					}},
+				}}},
+			},
+			expectARM64: true,
+			expectNotes: "Not pinned to an architecture; can schedule on Graviton nodes | Preferred affinity:",
+		},
+		{
+			name:         "ConfigMap",
+			manifest:     map[string]any{"kind": "ConfigMap"},
+			expectNotApp: true,
+			expectNotes:  "Not applicable: ConfigMap does not schedule pods",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Type: "kubernetes_manifest",
+				Name: "manifest",
+				Instances: []parser.ResourceInstance{
+					{Attributes: map[string]interface{}{"manifest": map[string]any{"value": tt.manifest, "type": []any{"object"}}}},
+				},
+			}, nil)
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if analysis.NotApplicable != tt.expectNotApp {
+				t.Errorf("Analyze() NotApplicable = %v, want %v", analysis.NotApplicable, tt.expectNotApp)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
+
+func TestHelmReleaseAnalyzer_Analyze(t *testing.T) {
+	analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+		Type: "helm_release",
+		Name: "ingress",
+		Instances: []parser.ResourceInstance{
+			{
+				Attributes: map[string]interface{}{
+					"metadata": []any{map[string]any{
+						"values": `{"controller":{"image":{"registry":"registry.k8s.io","repository":"ingress-nginx/controller","tag":"v1.11.0"}}}`,
+					}},
+					"set": []any{
+						map[string]any{"name": `controller.nodeSelector.kubernetes\.io/arch`, "value": "amd64"},
+					},
+				},
+			},
+		},
+	}, nil)
+
+	want := "Pinned to amd64 by set controller.nodeSelector.kubernetes\\.io/arch (amd64)"
+	if !strings.Contains(analysis.Notes, want) {
+		t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, want)
+	}
+	if !strings.Contains(analysis.Notes, "registry.k8s.io/ingress-nginx/controller:v1.11.0: not verified") {
+		t.Errorf("Analyze() Notes = %q, want the chart image", analysis.Notes)
+	}
+}
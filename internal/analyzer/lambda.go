@@ -1,8 +1,14 @@
 package analyzer
 
-import "github.com/suer/tf-arm/internal/parser"
+import (
+	"slices"
 
-type LambdaAnalyzer struct{}
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type LambdaAnalyzer struct {
+	ctx *Context
+}
 
 func (a *LambdaAnalyzer) SupportedType() string {
 	return "aws_lambda_function"
@@ -12,7 +18,7 @@ func (a *LambdaAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysi
 	analysis := ARM64Analysis{
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
-		ARM64Compatible: true,
+		ARM64Compatible: false,
 	}
 
 	for _, instance := range resource.Instances {
@@ -22,20 +28,159 @@ func (a *LambdaAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysi
 				continue
 			}
 			if len(archList) > 0 && archList[0] == "arm64" {
-				analysis.CurrentArch = "ARM64"
-				analysis.AlreadyUsingARM64 = true
+				analysis.setArchitecture(ArchitectureARM64)
 				analysis.RecommendedArch = "ARM64"
-				analysis.Notes = "Already using ARM64 architecture"
-			} else {
-				analysis.CurrentArch = "X86_64"
-				analysis.RecommendedArch = "ARM64"
-				analysis.Notes = "Can change architectures to [\"arm64\"]"
+				analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "architectures", "Already using ARM64 architecture")
+				continue
+			}
+			analysis.setArchitecture(ArchitectureX86_64)
+		} else {
+			analysis.setDefaultArchitecture(ArchitectureX86_64)
+		}
+
+		blockers, prerequisites := a.checkFunction(instance.Attributes)
+		if len(blockers) > 0 {
+			analysis.RecommendedArch = ""
+			analysis.decide(StatusBlocked, blockers[0].Code, blockers[0].Attribute, blockers[0].Message)
+			for _, blocker := range blockers[1:] {
+				analysis.recordFinding(blocker)
 			}
+			continue
+		}
+
+		analysis.RecommendedArch = "ARM64"
+		if analysis.ArchitectureDefault {
+			analysis.decide(StatusMigratable, FindingMigratable, "architectures", "Can add architectures = [\"arm64\"]")
 		} else {
-			analysis.CurrentArch = "X86_64 (default)"
-			analysis.RecommendedArch = "ARM64"
-			analysis.Notes = "Can add architectures = [\"arm64\"]"
+			analysis.decide(StatusMigratable, FindingMigratable, "architectures", "Can change architectures to [\"arm64\"]")
+		}
+		for _, prerequisite := range prerequisites {
+			analysis.recordFinding(prerequisite)
 		}
 	}
 	return analysis
 }
+
+// checkFunction returns the reasons the function cannot run on arm64 and the
+// steps that must be taken, or checks that could not be made, before switching
+// its architecture.
+func (a *LambdaAnalyzer) checkFunction(attributes map[string]any) (blockers, prerequisites []Finding) {
+	packageType, _ := attributes["package_type"].(string)
+	if packageType == "Image" {
+		// Lambda does not run multi-architecture images, so image_uri must
+		// point at an arm64 image whatever the image cache shows
+		imageURI, _ := attributes["image_uri"].(string)
+		if imageURI == "" {
+			prerequisites = append(prerequisites, newFinding(FindingMissingAttribute, "image_uri", "image_uri not in state; verify an arm64 image is available"))
+			return blockers, prerequisites
+		}
+		switch status := a.ctx.checkContainerImage(imageURI); status {
+		case containerImageX86Only:
+			blockers = append(blockers, newFinding(FindingBlocked, "image_uri", "Image "+imageURI+" has no arm64 variant"))
+		case containerImageARM64:
+			prerequisites = append(prerequisites, newFinding(FindingPrerequisite, "image_uri", "point image_uri at the arm64 variant of "+imageURI))
+		default:
+			prerequisites = append(prerequisites, newFinding(FindingPrerequisite, "image_uri", "point image_uri at an arm64 build of "+imageURI))
+			prerequisites = append(prerequisites, newFinding(FindingUnverified, "image_uri", "Image "+imageURI+" "+status+"; verify it has an arm64 variant"))
+		}
+	} else if runtime, ok := attributes["runtime"].(string); ok && runtime != "" {
+		if supported, known := getLambdaRuntimeARM64Support()[runtime]; known && !supported {
+			blockers = append(blockers, newFinding(FindingBlocked, "runtime", "Runtime "+runtime+" does not support arm64"))
+		} else if !known {
+			prerequisites = append(prerequisites, newFinding(FindingUnverified, "runtime", "Runtime "+runtime+" not recognised; verify arm64 support"))
+		}
+	}
+
+	layers, _ := attributes["layers"].([]any)
+	for _, layer := range layers {
+		layerARN, ok := layer.(string)
+		if !ok {
+			continue
+		}
+		compatible, found := a.lookupLayerArchitectures(layerARN)
+		switch {
+		case !found:
+			prerequisites = append(prerequisites, newFinding(FindingUnverified, "layers", "Verify layer "+layerARN+" supports arm64"))
+		case len(compatible) == 0:
+			prerequisites = append(prerequisites, newFinding(FindingUnverified, "layers", "Layer "+layerARN+" does not declare compatible_architectures; verify it supports arm64"))
+		case !slices.Contains(compatible, "arm64"):
+			blockers = append(blockers, newFinding(FindingBlocked, "layers", "Layer "+layerARN+" is not compatible with arm64"))
+		}
+	}
+	return blockers, prerequisites
+}
+
+// lookupLayerArchitectures finds the aws_lambda_layer_version with the given
+// ARN in the state and returns its compatible_architectures.
+func (a *LambdaAnalyzer) lookupLayerArchitectures(layerARN string) ([]string, bool) {
+	for _, layer := range a.ctx.FindResources("aws_lambda_layer_version") {
+		for _, instance := range layer.Instances {
+			arn, _ := instance.Attributes["arn"].(string)
+			if arn != layerARN {
+				continue
+			}
+			var architectures []string
+			if archList, ok := instance.Attributes["compatible_architectures"].([]any); ok {
+				for _, arch := range archList {
+					if archStr, ok := arch.(string); ok {
+						architectures = append(architectures, archStr)
+					}
+				}
+			}
+			return architectures, true
+		}
+	}
+	return nil, false
+}
+
+// getLambdaRuntimeARM64Support reports whether each Lambda runtime identifier
+// can run on arm64.
+func getLambdaRuntimeARM64Support() map[string]bool {
+	return map[string]bool{
+		// Node.js
+		"nodejs":     false,
+		"nodejs4.3":  false,
+		"nodejs6.10": false,
+		"nodejs8.10": false,
+		"nodejs10.x": false,
+		"nodejs12.x": false,
+		"nodejs14.x": true,
+		"nodejs16.x": true,
+		"nodejs18.x": true,
+		"nodejs20.x": true,
+		"nodejs22.x": true,
+		// Python
+		"python2.7":  false,
+		"python3.6":  false,
+		"python3.7":  false,
+		"python3.8":  true,
+		"python3.9":  true,
+		"python3.10": true,
+		"python3.11": true,
+		"python3.12": true,
+		"python3.13": true,
+		// Java
+		"java8":     false,
+		"java8.al2": true,
+		"java11":    true,
+		"java17":    true,
+		"java21":    true,
+		// .NET
+		"dotnetcore1.0": false,
+		"dotnetcore2.0": false,
+		"dotnetcore2.1": false,
+		"dotnetcore3.1": true,
+		"dotnet6":       true,
+		"dotnet8":       true,
+		// Ruby
+		"ruby2.5": false,
+		"ruby2.7": true,
+		"ruby3.2": true,
+		"ruby3.3": true,
+		// Go and custom runtimes
+		"go1.x":           false,
+		"provided":        false,
+		"provided.al2":    true,
+		"provided.al2023": true,
+	}
+}
@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestLambdaAnalyzer_Analyze(t *testing.T) {
+	state := &parser.TerraformState{
+		Version: 4,
+		Resources: []parser.TerraformResource{
+			{
+				Mode: "managed",
+				Type: "aws_lambda_layer_version",
+				Name: "x86_only",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"arn":                      "arn:aws:lambda:us-east-1:123456789012:layer:x86:1",
+							"compatible_architectures": []any{"x86_64"},
+						},
+					},
+				},
+			},
+			{
+				Mode: "managed",
+				Type: "aws_lambda_layer_version",
+				Name: "multi_arch",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"arn":                      "arn:aws:lambda:us-east-1:123456789012:layer:multi:3",
+							"compatible_architectures": []any{"x86_64", "arm64"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		attributes  map[string]interface{}
+		expectARM64 bool
+		expectNotes string
+	}{
+		{
+			name:        "supported runtime without architectures",
+			attributes:  map[string]interface{}{"runtime": "python3.12"},
+			expectARM64: true,
+			expectNotes: "Can add architectures = [\"arm64\"]",
+		},
+		{
+			name:        "go1.x runtime is blocked",
+			attributes:  map[string]interface{}{"runtime": "go1.x"},
+			expectARM64: false,
+			expectNotes: "Runtime go1.x does not support arm64",
+		},
+		{
+			name:        "container image needs an arm64 build",
+			attributes:  map[string]interface{}{"package_type": "Image", "image_uri": "123456789012.dkr.ecr.us-east-1.amazonaws.com/app:latest"},
+			expectARM64: true,
+			expectNotes: "Prerequisite: point image_uri at an arm64 build of 123456789012.dkr.ecr.us-east-1.amazonaws.com/app:latest",
+		},
+		{
+			name: "x86-only layer is blocked",
+			attributes: map[string]interface{}{
+				"runtime": "nodejs20.x",
+				"layers":  []any{"arn:aws:lambda:us-east-1:123456789012:layer:x86:1"},
+			},
+			expectARM64: false,
+			expectNotes: "is not compatible with arm64",
+		},
+		{
+			name: "multi-arch layer is accepted",
+			attributes: map[string]interface{}{
+				"runtime": "nodejs20.x",
+				"layers":  []any{"arn:aws:lambda:us-east-1:123456789012:layer:multi:3"},
+			},
+			expectARM64: true,
+			expectNotes: "Can add architectures = [\"arm64\"]",
+		},
+		{
+			name: "layer outside the state needs verification",
+			attributes: map[string]interface{}{
+				"runtime": "nodejs20.x",
+				"layers":  []any{"arn:aws:lambda:us-east-1:999999999999:layer:external:7"},
+			},
+			expectARM64: true,
+			expectNotes: "Verify layer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &LambdaAnalyzer{ctx: NewContext(state)}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type:      "aws_lambda_function",
+				Name:      "fn",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
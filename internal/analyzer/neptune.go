@@ -0,0 +1,36 @@
+package analyzer
+
+import (
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type NeptuneInstanceAnalyzer struct {
+	ctx *Context
+}
+
+func (a *NeptuneInstanceAnalyzer) SupportedType() string {
+	return "aws_neptune_cluster_instance"
+}
+
+func (a *NeptuneInstanceAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	return analyzeDBClusterInstance(a.ctx, resource, dbClusterEngine{
+		Name:         "neptune",
+		ClusterType:  "aws_neptune_cluster",
+		ARM64Classes: getNeptuneARM64InstanceClasses(),
+		Minimums:     getNeptuneEngineMinimums(),
+	})
+}
+
+// getNeptuneEngineMinimums lists the minimum engine version that supports
+// Graviton instance classes.
+func getNeptuneEngineMinimums() []string {
+	return []string{"1.1.0.0"}
+}
+
+func getNeptuneARM64InstanceClasses() []string {
+	return []string{
+		// Graviton2
+		"db.t4g.medium",
+		"db.r6g.large", "db.r6g.xlarge", "db.r6g.2xlarge", "db.r6g.4xlarge", "db.r6g.8xlarge", "db.r6g.12xlarge", "db.r6g.16xlarge",
+	}
+}
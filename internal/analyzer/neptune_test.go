@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestNeptuneInstanceAnalyzer_EngineVersion(t *testing.T) {
+	tests := []struct {
+		name                string
+		engineVersion       string
+		engineVersionActual string
+		expectNotes         string
+	}{
+		{
+			name:          "eligible engine",
+			engineVersion: "1.2.1.0",
+			expectNotes:   "Can migrate to ARM64 instance class: db.r6g.large",
+		},
+		{
+			name:          "old engine needs an upgrade",
+			engineVersion: "1.0.5.1",
+			expectNotes:   "Prerequisite: upgrade engine_version 1.0.5.1 -> 1.1.0.0",
+		},
+		{
+			name:                "engine_version_actual preferred over a stale engine_version",
+			engineVersion:       "1.0.5.1",
+			engineVersionActual: "1.2.1.0",
+			expectNotes:         "Can migrate to ARM64 instance class: db.r6g.large",
+		},
+		{
+			name:        "engine version unknown",
+			expectNotes: "engine_version not in state; neptune needs 1.1.0.0 or later",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &NeptuneInstanceAnalyzer{}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type: "aws_neptune_cluster_instance",
+				Name: "graph",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"instance_class":        "db.r5.large",
+							"engine_version":        tt.engineVersion,
+							"engine_version_actual": tt.engineVersionActual,
+						},
+					},
+				},
+			})
+
+			if !analysis.ARM64Compatible {
+				t.Errorf("Analyze() ARM64Compatible = false, want true")
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+			if !strings.Contains(tt.expectNotes, "Prerequisite") && strings.Contains(analysis.Notes, "Prerequisite") {
+				t.Errorf("Analyze() Notes = %q, want no prerequisite", analysis.Notes)
+			}
+		})
+	}
+}
@@ -1,6 +1,11 @@
 package analyzer
 
-import "github.com/suer/tf-arm/internal/parser"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
 
 type OpenSearchAnalyzer struct{}
 
@@ -13,47 +18,117 @@ func (a *OpenSearchAnalyzer) Analyze(resource parser.TerraformResource) ARM64Ana
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
 		ARM64Compatible: false,
-		CurrentArch:     "X86_64",
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
-		if clusterConfig, exists := instance.Attributes["cluster_config"]; exists {
-			clusterConfigList, ok := clusterConfig.([]any)
-			if !ok {
-				continue
-			}
-			if len(clusterConfigList) > 0 {
-				config, ok := clusterConfigList[0].(map[string]any)
-				if !ok {
-					continue
-				}
+		clusterConfigList, ok := instance.Attributes["cluster_config"].([]any)
+		if !ok || len(clusterConfigList) == 0 {
+			continue
+		}
+		config, ok := clusterConfigList[0].(map[string]any)
+		if !ok {
+			continue
+		}
 
-				if instanceType, exists := config["instance_type"]; exists {
-					instanceTypeStr, ok := instanceType.(string)
-					if !ok {
-						continue
-					}
+		roles := getOpenSearchRoles(config)
+		if len(roles) == 0 {
+			continue
+		}
+		applyOpenSearchRoles(&analysis, roles)
 
-					if isARM64OpenSearchInstanceType(instanceTypeStr) {
-						analysis.ARM64Compatible = true
-						analysis.CurrentArch = "ARM64"
-						analysis.RecommendedArch = "ARM64"
-						analysis.AlreadyUsingARM64 = true
-						analysis.Notes = "Already using ARM64 instance type"
-					} else if hasARM64OpenSearchAlternative(instanceTypeStr) {
-						analysis.ARM64Compatible = true
-						analysis.RecommendedArch = getARM64OpenSearchAlternative(instanceTypeStr)
-						analysis.Notes = "Can migrate to ARM64 instance type: " + analysis.RecommendedArch
-					} else {
-						analysis.Notes = "No ARM64 compatible instance type available"
-					}
-				}
+		// aws_elasticsearch_domain stores a bare version, aws_opensearch_domain
+		// prefixes it with the engine name
+		engineVersion, _ := instance.Attributes["engine_version"].(string)
+		versionAttribute := "engine_version"
+		if resource.Type == "aws_elasticsearch_domain" {
+			if version, ok := instance.Attributes["elasticsearch_version"].(string); ok && version != "" {
+				engineVersion = "Elasticsearch_" + version
+				versionAttribute = "elasticsearch_version"
 			}
 		}
+		if analysis.canMigrate() {
+			applyOpenSearchEngineEligibility(&analysis, versionAttribute, engineVersion)
+		}
 	}
 	return analysis
 }
 
+// openSearchRole is one node role of a domain and the instance type it uses.
+type openSearchRole struct {
+	Name         string
+	InstanceType string
+	Attribute    string
+}
+
+// getOpenSearchRoles returns the node roles enabled in cluster_config.
+func getOpenSearchRoles(config map[string]any) []openSearchRole {
+	var roles []openSearchRole
+	if instanceType, ok := config["instance_type"].(string); ok && instanceType != "" {
+		roles = append(roles, openSearchRole{Name: "data", InstanceType: instanceType, Attribute: "cluster_config.0.instance_type"})
+	}
+	if enabled, _ := config["dedicated_master_enabled"].(bool); enabled {
+		if instanceType, ok := config["dedicated_master_type"].(string); ok && instanceType != "" {
+			roles = append(roles, openSearchRole{Name: "master", InstanceType: instanceType, Attribute: "cluster_config.0.dedicated_master_type"})
+		}
+	}
+	if enabled, _ := config["warm_enabled"].(bool); enabled {
+		if instanceType, ok := config["warm_type"].(string); ok && instanceType != "" {
+			roles = append(roles, openSearchRole{Name: "warm", InstanceType: instanceType, Attribute: "cluster_config.0.warm_type"})
+		}
+	}
+	return roles
+}
+
+// applyOpenSearchRoles makes a Graviton decision per node role. UltraWarm
+// nodes have no Graviton option and do not affect the domain verdict.
+func applyOpenSearchRoles(analysis *ARM64Analysis, roles []openSearchRole) {
+	var findings []roleFinding
+	for _, role := range roles {
+		finding := roleFinding{Role: role.Name, Current: role.InstanceType, Attribute: role.Attribute}
+		// aws_elasticsearch_domain names the same instance types with an
+		// ".elasticsearch" suffix
+		name, legacy := strings.CutSuffix(role.InstanceType, ".elasticsearch")
+		instanceType := name + ".search"
+		if !legacy {
+			instanceType = role.InstanceType
+		}
+		switch {
+		case strings.HasPrefix(instanceType, "ultrawarm"):
+			finding.Status = roleSkipped
+			finding.Message = "UltraWarm has no Graviton option"
+		case isARM64OpenSearchInstanceType(instanceType):
+			finding.Status = roleGraviton
+			finding.Message = "already using ARM64"
+		case hasARM64OpenSearchAlternative(instanceType):
+			finding.Status = roleMigratable
+			finding.Recommendation = getARM64OpenSearchAlternative(instanceType)
+			if legacy {
+				finding.Recommendation = strings.TrimSuffix(finding.Recommendation, ".search") + ".elasticsearch"
+			}
+			finding.Message = "can migrate to " + finding.Recommendation
+		default:
+			finding.Status = roleBlocked
+			finding.Message = "no ARM64 compatible instance type available"
+		}
+		findings = append(findings, finding)
+	}
+	applyRoleFindings(analysis, findings, "node roles")
+}
+
+// applyOpenSearchEngineEligibility adds an engine upgrade as a prerequisite
+// when the domain runs an Elasticsearch version without Graviton support.
+// Every OpenSearch version supports Graviton.
+func applyOpenSearchEngineEligibility(analysis *ARM64Analysis, attribute, engineVersion string) {
+	version, found := strings.CutPrefix(engineVersion, "Elasticsearch_")
+	if !found {
+		return
+	}
+	if upgradeTo := getRequiredEngineUpgrade(version, []string{"7.9"}); upgradeTo != "" {
+		analysis.addFinding(FindingPrerequisite, attribute, fmt.Sprintf("upgrade %s -> Elasticsearch_%s or OpenSearch", engineVersion, upgradeTo))
+	}
+}
+
 type MSKAnalyzer struct{}
 
 func (a *MSKAnalyzer) SupportedType() string {
@@ -65,8 +140,8 @@ func (a *MSKAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
 		ARM64Compatible: false,
-		CurrentArch:     "X86_64",
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
 		if brokerNodeGroupInfo, exists := instance.Attributes["broker_node_group_info"]; exists {
@@ -86,18 +161,18 @@ func (a *MSKAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
 						continue
 					}
 
+					const attribute = "broker_node_group_info.0.instance_type"
 					if isARM64MSKInstanceType(instanceTypeStr) {
-						analysis.ARM64Compatible = true
-						analysis.CurrentArch = "ARM64"
+						analysis.setArchitecture(ArchitectureARM64)
 						analysis.RecommendedArch = "ARM64"
-						analysis.AlreadyUsingARM64 = true
-						analysis.Notes = "Already using ARM64 instance type"
+						analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, attribute, "Already using ARM64 instance type")
 					} else if hasARM64MSKAlternative(instanceTypeStr) {
-						analysis.ARM64Compatible = true
+						analysis.setArchitecture(ArchitectureX86_64)
 						analysis.RecommendedArch = getARM64MSKAlternative(instanceTypeStr)
-						analysis.Notes = "Can migrate to ARM64 instance type: " + analysis.RecommendedArch
+						analysis.decide(StatusMigratable, FindingMigratable, attribute, "Can migrate to ARM64 instance type: "+analysis.RecommendedArch)
 					} else {
-						analysis.Notes = "No ARM64 compatible instance type available"
+						analysis.setArchitecture(ArchitectureX86_64)
+						analysis.decide(StatusBlocked, FindingNoARM64Option, attribute, "No ARM64 compatible instance type available")
 					}
 				}
 			}
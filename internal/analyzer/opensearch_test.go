@@ -0,0 +1,195 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestOpenSearchAnalyzer_Analyze(t *testing.T) {
+	clusterConfig := func(config map[string]any) []any {
+		return []any{config}
+	}
+
+	tests := []struct {
+		name          string
+		resourceType  string
+		attributes    map[string]any
+		expectStatus  Status
+		expectArch    Architecture
+		expectCodes   []FindingCode
+		expectMessage string
+	}{
+		{
+			name:         "data nodes on OpenSearch",
+			resourceType: "aws_opensearch_domain",
+			attributes: map[string]any{
+				"engine_version": "OpenSearch_2.11",
+				"cluster_config": clusterConfig(map[string]any{"instance_type": "r5.large.search"}),
+			},
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote},
+			expectMessage: "data (r5.large.search): can migrate to r6g.large.search",
+		},
+		{
+			name:         "data and dedicated master nodes",
+			resourceType: "aws_opensearch_domain",
+			attributes: map[string]any{
+				"engine_version": "OpenSearch_2.11",
+				"cluster_config": clusterConfig(map[string]any{
+					"instance_type":            "m5.large.search",
+					"dedicated_master_enabled": true,
+					"dedicated_master_type":    "c5.large.search",
+				}),
+			},
+			expectStatus: StatusMigratable,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingMigratable, FindingNote, FindingNote},
+		},
+		{
+			name:         "dedicated master type ignored while disabled",
+			resourceType: "aws_opensearch_domain",
+			attributes: map[string]any{
+				"engine_version": "OpenSearch_2.11",
+				"cluster_config": clusterConfig(map[string]any{
+					"instance_type":            "r6g.large.search",
+					"dedicated_master_enabled": false,
+					"dedicated_master_type":    "c5.large.search",
+				}),
+			},
+			expectStatus: StatusAlreadyARM64,
+			expectArch:   ArchitectureARM64,
+			expectCodes:  []FindingCode{FindingAlreadyARM64, FindingNote},
+		},
+		{
+			name:         "Graviton data nodes with an x86_64 master",
+			resourceType: "aws_opensearch_domain",
+			attributes: map[string]any{
+				"engine_version": "OpenSearch_2.11",
+				"cluster_config": clusterConfig(map[string]any{
+					"instance_type":            "r6g.large.search",
+					"dedicated_master_enabled": true,
+					"dedicated_master_type":    "m5.large.search",
+				}),
+			},
+			expectStatus: StatusPartiallyMigrated,
+			expectArch:   ArchitectureMixed,
+			expectCodes:  []FindingCode{FindingPartiallyMigrated, FindingNote, FindingNote},
+		},
+		{
+			name:         "UltraWarm nodes do not affect the verdict",
+			resourceType: "aws_opensearch_domain",
+			attributes: map[string]any{
+				"engine_version": "OpenSearch_2.11",
+				"cluster_config": clusterConfig(map[string]any{
+					"instance_type": "r6g.large.search",
+					"warm_enabled":  true,
+					"warm_type":     "ultrawarm1.medium.search",
+				}),
+			},
+			expectStatus:  StatusAlreadyARM64,
+			expectArch:    ArchitectureARM64,
+			expectCodes:   []FindingCode{FindingAlreadyARM64, FindingNote, FindingNote},
+			expectMessage: "warm (ultrawarm1.medium.search): UltraWarm has no Graviton option",
+		},
+		{
+			name:         "master without a Graviton option blocks the domain",
+			resourceType: "aws_opensearch_domain",
+			attributes: map[string]any{
+				"engine_version": "OpenSearch_2.11",
+				"cluster_config": clusterConfig(map[string]any{
+					"instance_type":            "r5.large.search",
+					"dedicated_master_enabled": true,
+					"dedicated_master_type":    "i3.large.search",
+				}),
+			},
+			expectStatus: StatusBlocked,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingNoARM64Option, FindingNote, FindingNoARM64Option},
+		},
+		{
+			name:         "Elasticsearch below 7.9 on aws_opensearch_domain",
+			resourceType: "aws_opensearch_domain",
+			attributes: map[string]any{
+				"engine_version": "Elasticsearch_7.4",
+				"cluster_config": clusterConfig(map[string]any{"instance_type": "m5.large.search"}),
+			},
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote, FindingPrerequisite},
+			expectMessage: "upgrade Elasticsearch_7.4 -> Elasticsearch_7.9 or OpenSearch",
+		},
+		{
+			name:         "Elasticsearch 7.10 meets the minimum",
+			resourceType: "aws_opensearch_domain",
+			attributes: map[string]any{
+				"engine_version": "Elasticsearch_7.10",
+				"cluster_config": clusterConfig(map[string]any{"instance_type": "m5.large.search"}),
+			},
+			expectStatus: StatusMigratable,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingMigratable, FindingNote},
+		},
+		{
+			name:         "aws_elasticsearch_domain on Elasticsearch 6.8",
+			resourceType: "aws_elasticsearch_domain",
+			attributes: map[string]any{
+				"elasticsearch_version": "6.8",
+				"cluster_config":        clusterConfig(map[string]any{"instance_type": "c5.large.elasticsearch"}),
+			},
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote, FindingPrerequisite},
+			expectMessage: "upgrade Elasticsearch_6.8 -> Elasticsearch_7.9 or OpenSearch",
+		},
+		{
+			name:         "aws_elasticsearch_domain on 7.10",
+			resourceType: "aws_elasticsearch_domain",
+			attributes: map[string]any{
+				"elasticsearch_version": "7.10",
+				"cluster_config":        clusterConfig(map[string]any{"instance_type": "r5.large.elasticsearch"}),
+			},
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote},
+			expectMessage: "data (r5.large.elasticsearch): can migrate to r6g.large.elasticsearch",
+		},
+		{
+			name:         "aws_elasticsearch_domain on Graviton",
+			resourceType: "aws_elasticsearch_domain",
+			attributes: map[string]any{
+				"elasticsearch_version": "7.10",
+				"cluster_config":        clusterConfig(map[string]any{"instance_type": "m6g.large.elasticsearch"}),
+			},
+			expectStatus: StatusAlreadyARM64,
+			expectArch:   ArchitectureARM64,
+			expectCodes:  []FindingCode{FindingAlreadyARM64, FindingNote},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResource(parser.TerraformResource{
+				Mode:      "managed",
+				Type:      tt.resourceType,
+				Name:      "search",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v (notes: %s)", analysis.Status, tt.expectStatus, analysis.Notes)
+			}
+			if analysis.Architecture != tt.expectArch {
+				t.Errorf("Architecture = %v, want %v", analysis.Architecture, tt.expectArch)
+			}
+			if codes := getFindingCodes(analysis); !slices.Equal(codes, tt.expectCodes) {
+				t.Errorf("finding codes = %v, want %v", codes, tt.expectCodes)
+			}
+			if tt.expectMessage != "" && !slices.ContainsFunc(analysis.Findings, func(finding Finding) bool { return finding.Message == tt.expectMessage }) {
+				t.Errorf("Findings = %+v, want message %q", analysis.Findings, tt.expectMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package analyzer
+
+import "strings"
+
+// isWindowsOperatingSystem reports whether an operating system value, in any
+// of the spellings used by EC2, ECS and GameLift, refers to Windows.
+func isWindowsOperatingSystem(operatingSystem string) bool {
+	return strings.HasPrefix(strings.ToUpper(operatingSystem), "WINDOWS")
+}
+
+// markNotApplicableWindows records that a resource runs Windows, which has no
+// ARM64 support on AWS or Google Cloud, so it is excluded from migration
+// statistics. Azure runs Windows 11 on Arm64 VM sizes but not Windows Server,
+// which its server workloads use, so they are excluded there too.
+func markNotApplicableWindows(analysis *ARM64Analysis, operatingSystem string) {
+	analysis.setArchitecture(ArchitectureX86_64)
+	analysis.RecommendedArch = ""
+	analysis.decide(StatusNotApplicable, FindingNotApplicable, "", "Windows ("+operatingSystem+") cannot run on ARM64")
+}
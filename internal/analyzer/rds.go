@@ -1,8 +1,16 @@
 package analyzer
 
-import "github.com/suer/tf-arm/internal/parser"
+import (
+	"fmt"
+	"strconv"
+	"strings"
 
-type RDSAnalyzer struct{}
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type RDSAnalyzer struct {
+	ctx *Context
+}
 
 func (a *RDSAnalyzer) SupportedType() string {
 	return "aws_db_instance"
@@ -13,8 +21,8 @@ func (a *RDSAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
 		ARM64Compatible: false,
-		CurrentArch:     "X86_64",
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
 		if instanceClass, exists := instance.Attributes["instance_class"]; exists {
@@ -23,25 +31,166 @@ func (a *RDSAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
 				continue
 			}
 
-			if isARM64RDSInstanceClass(instanceClassStr) {
-				analysis.ARM64Compatible = true
-				analysis.CurrentArch = "ARM64"
-				analysis.RecommendedArch = "ARM64"
-				analysis.AlreadyUsingARM64 = true
-				analysis.Notes = "Already using ARM64 instance class"
-			} else if hasARM64RDSAlternative(instanceClassStr) {
-				analysis.ARM64Compatible = true
-				analysis.RecommendedArch = getARM64RDSAlternative(instanceClassStr)
-				analysis.Notes = "Can migrate to ARM64 instance class: " + analysis.RecommendedArch
-			} else {
-				analysis.Notes = "No ARM64 compatible instance class available"
+			applyRDSInstanceClass(&analysis, "instance_class", instanceClassStr)
+			if !analysis.canMigrate() {
+				continue
 			}
+
+			engine, _ := instance.Attributes["engine"].(string)
+			engineVersion, _, _ := a.ctx.lookupAttribute(resource, instance.Attributes, "engine_version")
+			engineVersionStr, _ := engineVersion.(string)
+			applyRDSEngineEligibility(&analysis, engine, engineVersionStr)
 		}
 	}
 	return analysis
 }
 
-type AuroraAnalyzer struct{}
+// applyRDSEngineEligibility checks that the engine can run on the recommended
+// Graviton class and adds any engine upgrade as a prerequisite step.
+func applyRDSEngineEligibility(analysis *ARM64Analysis, engine, engineVersion string) {
+	if engine == "" {
+		analysis.addFinding(FindingMissingAttribute, "engine", "engine not in state; verify Graviton eligibility")
+		return
+	}
+
+	eligibility, known := getRDSEngineEligibility()[engine]
+	if !known {
+		analysis.addFinding(FindingUnverified, "engine", "Verify engine "+engine+" supports Graviton instance classes")
+		return
+	}
+
+	minimums := eligibility[getGravitonGeneration(analysis.RecommendedArch)]
+	if len(minimums) == 0 {
+		analysis.RecommendedArch = ""
+		analysis.decide(StatusBlocked, FindingBlocked, "engine", "Engine "+engine+" does not support Graviton instance classes")
+		return
+	}
+
+	applyEngineVersionMinimum(analysis, engine, engineVersion, minimums)
+}
+
+// applyEngineVersionMinimum adds an engine upgrade as a prerequisite step when
+// engineVersion is older than the minimums for the recommended class.
+func applyEngineVersionMinimum(analysis *ARM64Analysis, engine, engineVersion string, minimums []string) {
+	if engineVersion == "" {
+		analysis.addFinding(FindingMissingAttribute, "engine_version", "engine_version not in state; "+engine+" needs "+strings.Join(minimums, ", ")+" or later on "+analysis.RecommendedArch)
+		return
+	}
+
+	if upgradeTo := getRequiredEngineUpgrade(engineVersion, minimums); upgradeTo != "" {
+		analysis.addFinding(FindingPrerequisite, "engine_version", fmt.Sprintf("upgrade engine_version %s -> %s", engineVersion, upgradeTo))
+	}
+}
+
+// getRequiredEngineUpgrade returns the version to upgrade to, or an empty
+// string when the version already meets the minimum for its release line.
+// Minimums are ordered oldest first, one per release line; versions on a
+// release line newer than every listed one are eligible.
+func getRequiredEngineUpgrade(version string, minimums []string) string {
+	for _, minimum := range minimums {
+		line := minimum[:strings.LastIndex(minimum, ".")]
+		switch {
+		case compareVersions(version, line) < 0:
+			// Older release line than any eligible one
+			return minimum
+		case version == line || strings.HasPrefix(version, line+"."):
+			if compareVersions(version, minimum) < 0 {
+				return minimum
+			}
+			return ""
+		}
+	}
+	return ""
+}
+
+// compareVersions compares dotted numeric versions, ignoring any non-numeric
+// suffix such as "-R2" or ".mysql_aurora.3.02.0".
+func compareVersions(a, b string) int {
+	aParts, bParts := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aNum, bNum int
+		if i < len(aParts) {
+			aNum, _ = strconv.Atoi(strings.TrimRightFunc(aParts[i], func(r rune) bool { return r < '0' || r > '9' }))
+		}
+		if i < len(bParts) {
+			bNum, _ = strconv.Atoi(strings.TrimRightFunc(bParts[i], func(r rune) bool { return r < '0' || r > '9' }))
+		}
+		if aNum != bNum {
+			if aNum < bNum {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// getGravitonGeneration returns the Graviton generation of a db. or cache.
+// class.
+func getGravitonGeneration(instanceClass string) int {
+	for _, prefix := range []string{"db.", "cache."} {
+		instanceClass = strings.TrimPrefix(instanceClass, prefix)
+	}
+	family, _, _ := strings.Cut(instanceClass, ".")
+	switch family {
+	case "t4g", "m6g", "m6gd", "r6g", "r6gd", "x2g":
+		return 2
+	case "m7g", "r7g":
+		return 3
+	default:
+		return 4
+	}
+}
+
+// getRDSEngineEligibility lists, per engine and Graviton generation, the
+// minimum engine version on each eligible release line. Engines with no
+// entries for a generation cannot use it.
+func getRDSEngineEligibility() map[string]map[int][]string {
+	noGraviton := map[int][]string{}
+	return map[string]map[int][]string{
+		"mysql": {
+			2: {"8.0.17"},
+			3: {"8.0.28"},
+			4: {"8.0.39"},
+		},
+		"mariadb": {
+			2: {"10.4.13"},
+			3: {"10.4.29", "10.5.20", "10.6.13"},
+			4: {"10.11.9"},
+		},
+		"postgres": {
+			2: {"12.7", "13.3"},
+			3: {"13.4", "14.5", "15.2"},
+			4: {"13.15", "14.12", "15.7", "16.3"},
+		},
+		// Aurora MySQL 2 lists each minor release line, as it shares the
+		// 5.7 prefix across them
+		"aurora-mysql": {
+			2: {"5.7.mysql_aurora.2.09.2", "5.7.mysql_aurora.2.10.0", "5.7.mysql_aurora.2.11.0", "5.7.mysql_aurora.2.12.0", "8.0.mysql_aurora.3.01.0"},
+			3: {"8.0.mysql_aurora.3.03.1"},
+			4: {"8.0.mysql_aurora.3.08.0"},
+		},
+		"aurora-postgresql": {
+			2: {"11.9", "12.4", "13.3"},
+			3: {"13.10", "14.7", "15.2"},
+			4: {"13.15", "14.12", "15.7", "16.3"},
+		},
+		"oracle-ee":      noGraviton,
+		"oracle-ee-cdb":  noGraviton,
+		"oracle-se2":     noGraviton,
+		"oracle-se2-cdb": noGraviton,
+		"sqlserver-ee":   noGraviton,
+		"sqlserver-se":   noGraviton,
+		"sqlserver-ex":   noGraviton,
+		"sqlserver-web":  noGraviton,
+		"db2-ae":         noGraviton,
+		"db2-se":         noGraviton,
+	}
+}
+
+type AuroraAnalyzer struct {
+	ctx *Context
+}
 
 func (a *AuroraAnalyzer) SupportedType() string {
 	return "aws_rds_cluster"
@@ -52,29 +201,188 @@ func (a *AuroraAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysi
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
 		ARM64Compatible: false,
-		CurrentArch:     "X86_64",
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
-		if engine, exists := instance.Attributes["engine"]; exists {
-			engineStr, ok := engine.(string)
-			if !ok {
+		// Multi-AZ DB clusters size the cluster itself rather than its instances
+		if clusterClass, ok := instance.Attributes["db_cluster_instance_class"].(string); ok && clusterClass != "" {
+			applyRDSInstanceClass(&analysis, "db_cluster_instance_class", clusterClass)
+			continue
+		}
+
+		engine, exists := instance.Attributes["engine"]
+		if !exists {
+			continue
+		}
+		engineStr, ok := engine.(string)
+		if !ok {
+			continue
+		}
+
+		// Aurora supports ARM64 for MySQL and PostgreSQL
+		if engineStr != "aurora-mysql" && engineStr != "aurora-postgresql" {
+			analysis.decide(StatusBlocked, FindingBlocked, "engine", "Engine "+engineStr+" may not support ARM64")
+			continue
+		}
+
+		clusterIdentifier, _ := instance.Attributes["cluster_identifier"].(string)
+		members := a.findClusterInstances(clusterIdentifier)
+		if len(members) == 0 {
+			analysis.RecommendedArch = "ARM64"
+			analysis.decide(StatusMigratable, FindingMigratable, "engine", "Aurora "+engineStr+" supports ARM64 with compatible instance classes")
+			analysis.addFinding(FindingMissingAttribute, "cluster_identifier", "no aws_rds_cluster_instance found in state for this cluster")
+			continue
+		}
+		analysis.RollUp = true
+		rollUpAuroraCluster(&analysis, members)
+	}
+	return analysis
+}
+
+// auroraClusterMember is one writer or reader of an Aurora cluster.
+type auroraClusterMember struct {
+	Address       string
+	InstanceClass string
+	Writer        bool
+}
+
+// findClusterInstances returns the aws_rds_cluster_instance resources in the
+// state that belong to the cluster with the given identifier.
+func (a *AuroraAnalyzer) findClusterInstances(clusterIdentifier string) []auroraClusterMember {
+	if clusterIdentifier == "" {
+		return nil
+	}
+
+	var members []auroraClusterMember
+	for _, resource := range a.ctx.FindResources("aws_rds_cluster_instance") {
+		for _, instance := range resource.Instances {
+			if id, _ := instance.Attributes["cluster_identifier"].(string); id != clusterIdentifier {
 				continue
 			}
+			instanceClass, _ := instance.Attributes["instance_class"].(string)
+			writer, _ := instance.Attributes["writer"].(bool)
+			members = append(members, auroraClusterMember{
+				Address:       resource.GetFullAddress(),
+				InstanceClass: instanceClass,
+				Writer:        writer,
+			})
+		}
+	}
+	return members
+}
+
+// rollUpAuroraCluster derives the cluster verdict from its instances: the
+// cluster is only fully migrated once every writer and reader is on Graviton.
+func rollUpAuroraCluster(analysis *ARM64Analysis, members []auroraClusterMember) {
+	var graviton, remaining, blocked []string
+	var provisioned int
+	for _, member := range members {
+		role := "reader"
+		if member.Writer {
+			role = "writer"
+		}
+		label := member.Address + " (" + role + ", " + member.InstanceClass + ")"
+
+		switch {
+		case member.InstanceClass == "db.serverless":
+			continue
+		case isARM64RDSInstanceClass(member.InstanceClass):
+			graviton = append(graviton, label)
+		case hasARM64RDSAlternative(member.InstanceClass):
+			remaining = append(remaining, label+" -> "+getARM64RDSAlternative(member.InstanceClass))
+		default:
+			blocked = append(blocked, label)
+		}
+		provisioned++
+	}
+
+	switch {
+	case provisioned == 0:
+		analysis.setArchitecture(ArchitectureManaged)
+		analysis.decide(StatusNotApplicable, FindingNotApplicable, "", "all cluster instances use Aurora Serverless v2 (db.serverless)")
+	case len(graviton) == provisioned:
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "", fmt.Sprintf("Fully migrated: all %d writer and reader instances use Graviton", provisioned))
+	case len(blocked) > 0:
+		analysis.decide(StatusBlocked, FindingNoARM64Option, "", "No ARM64 compatible instance class available for "+strings.Join(blocked, ", "))
+	default:
+		analysis.RecommendedArch = "ARM64"
+		if len(graviton) > 0 {
+			analysis.setArchitecture(ArchitectureMixed)
+			analysis.decide(StatusPartiallyMigrated, FindingPartiallyMigrated, "", fmt.Sprintf("Partially migrated: %d of %d instances use Graviton", len(graviton), provisioned))
+			analysis.addFinding(FindingNote, "", "Remaining: "+strings.Join(remaining, ", "))
+		} else {
+			analysis.decide(StatusMigratable, FindingMigratable, "", "Can migrate cluster instances: "+strings.Join(remaining, ", "))
+		}
+	}
+}
+
+type AuroraInstanceAnalyzer struct {
+	ctx *Context
+}
+
+func (a *AuroraInstanceAnalyzer) SupportedType() string {
+	return "aws_rds_cluster_instance"
+}
 
-			// Aurora supports ARM64 for MySQL and PostgreSQL
-			if engineStr == "aurora-mysql" || engineStr == "aurora-postgresql" {
-				analysis.ARM64Compatible = true
-				analysis.RecommendedArch = "ARM64"
-				analysis.Notes = "Aurora " + engineStr + " supports ARM64 with compatible instance classes"
-			} else {
-				analysis.Notes = "Engine " + engineStr + " may not support ARM64"
+func (a *AuroraInstanceAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analysis {
+	analysis := ARM64Analysis{
+		ResourceType:    resource.Type,
+		ResourceName:    resource.Name,
+		ARM64Compatible: false,
+	}
+	analysis.setArchitecture(ArchitectureX86_64)
+
+	for _, instance := range resource.Instances {
+		instanceClass, ok := instance.Attributes["instance_class"].(string)
+		if !ok {
+			continue
+		}
+
+		if instanceClass == "db.serverless" {
+			analysis.setArchitecture(ArchitectureManaged)
+			analysis.decide(StatusNotApplicable, FindingNotApplicable, "instance_class", "Aurora Serverless v2 (db.serverless) capacity is managed by AWS")
+			continue
+		}
+		applyRDSInstanceClass(&analysis, "instance_class", instanceClass)
+
+		clusterIdentifier, _ := instance.Attributes["cluster_identifier"].(string)
+		if analysis.canMigrate() {
+			// Cluster instances inherit the engine version of their cluster
+			engine, _ := instance.Attributes["engine"].(string)
+			engineVersion, _, _ := a.ctx.lookupAttribute(resource, instance.Attributes, "engine_version")
+			engineVersionStr, _ := engineVersion.(string)
+			if engineVersionStr == "" {
+				engineVersionStr = a.ctx.findClusterEngineVersion("aws_rds_cluster", clusterIdentifier)
 			}
+			applyRDSEngineEligibility(&analysis, engine, engineVersionStr)
+		}
+		if clusterIdentifier != "" {
+			analysis.addFinding(FindingNote, "cluster_identifier", "Member of cluster "+clusterIdentifier)
 		}
 	}
 	return analysis
 }
 
+// applyRDSInstanceClass records the Graviton decision for an RDS instance
+// class using the RDS class tables.
+func applyRDSInstanceClass(analysis *ARM64Analysis, attribute, instanceClass string) {
+	if isARM64RDSInstanceClass(instanceClass) {
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, attribute, "Already using ARM64 instance class")
+	} else if hasARM64RDSAlternative(instanceClass) {
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.RecommendedArch = getARM64RDSAlternative(instanceClass)
+		analysis.decide(StatusMigratable, FindingMigratable, attribute, "Can migrate to ARM64 instance class: "+analysis.RecommendedArch)
+	} else {
+		analysis.setArchitecture(ArchitectureX86_64)
+		analysis.decide(StatusBlocked, FindingNoARM64Option, attribute, "No ARM64 compatible instance class available")
+	}
+}
+
 func isARM64RDSInstanceClass(instanceClass string) bool {
 	arm64Classes := []string{
 		// Graviton2
@@ -106,13 +414,13 @@ func getARM64RDSAlternative(instanceClass string) string {
 func getRDSX86ToArm64Map() map[string]string {
 	return map[string]string{
 		// T3 -> T4g (Graviton2)
-		"db.t3.nano":     "db.t4g.nano",
-		"db.t3.micro":    "db.t4g.micro",
-		"db.t3.small":    "db.t4g.small",
-		"db.t3.medium":   "db.t4g.medium",
-		"db.t3.large":    "db.t4g.large",
-		"db.t3.xlarge":   "db.t4g.xlarge",
-		"db.t3.2xlarge":  "db.t4g.2xlarge",
+		"db.t3.nano":    "db.t4g.nano",
+		"db.t3.micro":   "db.t4g.micro",
+		"db.t3.small":   "db.t4g.small",
+		"db.t3.medium":  "db.t4g.medium",
+		"db.t3.large":   "db.t4g.large",
+		"db.t3.xlarge":  "db.t4g.xlarge",
+		"db.t3.2xlarge": "db.t4g.2xlarge",
 		// M5 -> M7g (Graviton3 - better performance than M6g)
 		"db.m5.large":    "db.m7g.large",
 		"db.m5.xlarge":   "db.m7g.xlarge",
@@ -121,6 +429,13 @@ func getRDSX86ToArm64Map() map[string]string {
 		"db.m5.8xlarge":  "db.m7g.8xlarge",
 		"db.m5.12xlarge": "db.m7g.12xlarge",
 		"db.m5.16xlarge": "db.m7g.16xlarge",
+		// R4 -> R7g (Graviton3)
+		"db.r4.large":    "db.r7g.large",
+		"db.r4.xlarge":   "db.r7g.xlarge",
+		"db.r4.2xlarge":  "db.r7g.2xlarge",
+		"db.r4.4xlarge":  "db.r7g.4xlarge",
+		"db.r4.8xlarge":  "db.r7g.8xlarge",
+		"db.r4.16xlarge": "db.r7g.16xlarge",
 		// R5 -> R7g (Graviton3 - better performance than R6g)
 		"db.r5.large":    "db.r7g.large",
 		"db.r5.xlarge":   "db.r7g.xlarge",
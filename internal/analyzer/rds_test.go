@@ -0,0 +1,343 @@
+package analyzer
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestAuroraAnalyzer_ClusterRollup(t *testing.T) {
+	clusterInstance := func(name, class string, writer bool) parser.TerraformResource {
+		return parser.TerraformResource{
+			Mode: "managed",
+			Type: "aws_rds_cluster_instance",
+			Name: name,
+			Instances: []parser.ResourceInstance{
+				{
+					Attributes: map[string]interface{}{
+						"cluster_identifier": "main",
+						"instance_class":     class,
+						"writer":             writer,
+					},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name         string
+		members      []parser.TerraformResource
+		expectARM64  bool
+		expectUsing  bool
+		expectNotApp bool
+		expectNotes  string
+	}{
+		{
+			name: "all instances on Graviton",
+			members: []parser.TerraformResource{
+				clusterInstance("writer", "db.r7g.large", true),
+				clusterInstance("reader", "db.r6g.large", false),
+			},
+			expectARM64: true,
+			expectUsing: true,
+			expectNotes: "Fully migrated: all 2 writer and reader instances use Graviton",
+		},
+		{
+			name: "reader still on x86",
+			members: []parser.TerraformResource{
+				clusterInstance("writer", "db.r7g.large", true),
+				clusterInstance("reader", "db.r5.large", false),
+			},
+			expectARM64: true,
+			expectNotes: "Partially migrated: 1 of 2 instances use Graviton",
+		},
+		{
+			name: "serverless members are ignored",
+			members: []parser.TerraformResource{
+				clusterInstance("writer", "db.r5.large", true),
+				clusterInstance("reader", "db.serverless", false),
+			},
+			expectARM64: true,
+			expectNotes: "aws_rds_cluster_instance.writer (writer, db.r5.large) -> db.r7g.large",
+		},
+		{
+			name: "serverless-only cluster is not applicable",
+			members: []parser.TerraformResource{
+				clusterInstance("writer", "db.serverless", true),
+			},
+			expectNotApp: true,
+			expectNotes:  "Not applicable",
+		},
+		{
+			name:        "no instances in state",
+			expectARM64: true,
+			expectNotes: "no aws_rds_cluster_instance found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &parser.TerraformState{Version: 4, Resources: tt.members}
+			analyzer := &AuroraAnalyzer{ctx: NewContext(state)}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type: "aws_rds_cluster",
+				Name: "main",
+				Instances: []parser.ResourceInstance{
+					{
+						Attributes: map[string]interface{}{
+							"cluster_identifier": "main",
+							"engine":             "aurora-postgresql",
+						},
+					},
+				},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if analysis.NotApplicable != tt.expectNotApp {
+				t.Errorf("Analyze() NotApplicable = %v, want %v", analysis.NotApplicable, tt.expectNotApp)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
+
+func TestRDSAnalyzer_EngineEligibility(t *testing.T) {
+	tests := []struct {
+		name            string
+		attributes      map[string]interface{}
+		expectARM64     bool
+		expectRecommend string
+		expectNotes     string
+	}{
+		{
+			name: "eligible MySQL version",
+			attributes: map[string]interface{}{
+				"instance_class": "db.m5.large",
+				"engine":         "mysql",
+				"engine_version": "8.0.35",
+			},
+			expectARM64:     true,
+			expectRecommend: "db.m7g.large",
+			expectNotes:     "Can migrate to ARM64 instance class: db.m7g.large",
+		},
+		{
+			name: "MySQL upgrade required",
+			attributes: map[string]interface{}{
+				"instance_class": "db.m5.large",
+				"engine":         "mysql",
+				"engine_version": "8.0.23",
+			},
+			expectARM64:     true,
+			expectRecommend: "db.m7g.large",
+			expectNotes:     "Prerequisite: upgrade engine_version 8.0.23 -> 8.0.28",
+		},
+		{
+			name: "major version resolved through engine_version_actual",
+			attributes: map[string]interface{}{
+				"instance_class":        "db.m5.large",
+				"engine":                "mysql",
+				"engine_version":        "8.0",
+				"engine_version_actual": "8.0.35",
+			},
+			expectARM64:     true,
+			expectRecommend: "db.m7g.large",
+			expectNotes:     "Can migrate to ARM64 instance class: db.m7g.large",
+		},
+		{
+			name: "upgrade checked against engine_version_actual",
+			attributes: map[string]interface{}{
+				"instance_class":        "db.m5.large",
+				"engine":                "mysql",
+				"engine_version":        "8.0",
+				"engine_version_actual": "8.0.23",
+			},
+			expectARM64:     true,
+			expectRecommend: "db.m7g.large",
+			expectNotes:     "Prerequisite: upgrade engine_version 8.0.23 -> 8.0.28",
+		},
+		{
+			name: "MySQL 5.7 needs major upgrade",
+			attributes: map[string]interface{}{
+				"instance_class": "db.t3.micro",
+				"engine":         "mysql",
+				"engine_version": "5.7.44",
+			},
+			expectARM64:     true,
+			expectRecommend: "db.t4g.micro",
+			expectNotes:     "Prerequisite: upgrade engine_version 5.7.44 -> 8.0.17",
+		},
+		{
+			name: "PostgreSQL on a newer release line",
+			attributes: map[string]interface{}{
+				"instance_class": "db.r5.large",
+				"engine":         "postgres",
+				"engine_version": "16.1",
+			},
+			expectARM64:     true,
+			expectRecommend: "db.r7g.large",
+			expectNotes:     "Can migrate to ARM64 instance class: db.r7g.large",
+		},
+		{
+			name: "SQL Server is not eligible",
+			attributes: map[string]interface{}{
+				"instance_class": "db.m5.large",
+				"engine":         "sqlserver-se",
+				"engine_version": "15.00.4316.3.v1",
+			},
+			expectARM64: false,
+			expectNotes: "Engine sqlserver-se does not support Graviton instance classes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analyzer := &RDSAnalyzer{}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type:      "aws_db_instance",
+				Name:      "db",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.ARM64Compatible != tt.expectARM64 {
+				t.Errorf("Analyze() ARM64Compatible = %v, want %v", analysis.ARM64Compatible, tt.expectARM64)
+			}
+			if analysis.RecommendedArch != tt.expectRecommend {
+				t.Errorf("Analyze() RecommendedArch = %v, want %v", analysis.RecommendedArch, tt.expectRecommend)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+			if !strings.Contains(tt.expectNotes, "Prerequisite") && strings.Contains(analysis.Notes, "Prerequisite") {
+				t.Errorf("Analyze() Notes = %q, want no prerequisite", analysis.Notes)
+			}
+		})
+	}
+}
+
+func TestGetRequiredEngineUpgrade(t *testing.T) {
+	minimums := []string{"10.4.29", "10.5.20", "10.6.13"}
+	tests := []struct {
+		version  string
+		expected string
+	}{
+		{"10.3.39", "10.4.29"},
+		{"10.4.28", "10.4.29"},
+		{"10.4.29", ""},
+		{"10.5.9", "10.5.20"},
+		{"10.6.14", ""},
+		{"10.11.6", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if result := getRequiredEngineUpgrade(tt.version, minimums); result != tt.expected {
+				t.Errorf("getRequiredEngineUpgrade(%q) = %q, want %q", tt.version, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAuroraInstanceAnalyzer_EngineEligibility(t *testing.T) {
+	cluster := parser.TerraformResource{
+		Mode: "managed",
+		Type: "aws_rds_cluster",
+		Name: "main",
+		Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+			"cluster_identifier":    "main",
+			"engine":                "aurora-mysql",
+			"engine_version":        "5.7",
+			"engine_version_actual": "5.7.mysql_aurora.2.11.2",
+		}}},
+	}
+
+	tests := []struct {
+		name          string
+		attributes    map[string]any
+		expectStatus  Status
+		expectCodes   []FindingCode
+		expectMessage string
+	}{
+		{
+			name: "eligible Aurora PostgreSQL version",
+			attributes: map[string]any{
+				"instance_class": "db.r5.large",
+				"engine":         "aurora-postgresql",
+				"engine_version": "15.4",
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable},
+			expectMessage: "Can migrate to ARM64 instance class: db.r7g.large",
+		},
+		{
+			name: "Aurora PostgreSQL upgrade required",
+			attributes: map[string]any{
+				"instance_class": "db.r5.large",
+				"engine":         "aurora-postgresql",
+				"engine_version": "14.3",
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingPrerequisite},
+			expectMessage: "upgrade engine_version 14.3 -> 14.7",
+		},
+		{
+			name: "engine version inherited from the cluster",
+			attributes: map[string]any{
+				"instance_class":     "db.r5.large",
+				"engine":             "aurora-mysql",
+				"cluster_identifier": "main",
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingPrerequisite, FindingNote},
+			expectMessage: "upgrade engine_version 5.7.mysql_aurora.2.11.2 -> 8.0.mysql_aurora.3.03.1",
+		},
+		{
+			name: "engine not in state",
+			attributes: map[string]any{
+				"instance_class": "db.r5.large",
+			},
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable, FindingMissingAttribute},
+			expectMessage: "engine not in state; verify Graviton eligibility",
+		},
+		{
+			name: "Graviton instance is not checked",
+			attributes: map[string]any{
+				"instance_class": "db.r6g.large",
+				"engine":         "aurora-mysql",
+				"engine_version": "5.7.mysql_aurora.2.07.2",
+			},
+			expectStatus: StatusAlreadyARM64,
+			expectCodes:  []FindingCode{FindingAlreadyARM64},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := NewContext(&parser.TerraformState{Version: 4, Resources: []parser.TerraformResource{cluster}})
+			analysis := (&AuroraInstanceAnalyzer{ctx: ctx}).Analyze(parser.TerraformResource{
+				Type:      "aws_rds_cluster_instance",
+				Name:      "writer",
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v (notes: %s)", analysis.Status, tt.expectStatus, analysis.Notes)
+			}
+			if codes := getFindingCodes(analysis); !slices.Equal(codes, tt.expectCodes) {
+				t.Errorf("Findings = %v, want %v", codes, tt.expectCodes)
+			}
+			if tt.expectMessage != "" && !slices.ContainsFunc(analysis.Findings, func(finding Finding) bool { return finding.Message == tt.expectMessage }) {
+				t.Errorf("Findings = %+v, want message %q", analysis.Findings, tt.expectMessage)
+			}
+		})
+	}
+}
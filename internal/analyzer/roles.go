@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+type roleStatus int
+
+const (
+	roleGraviton roleStatus = iota
+	roleMigratable
+	roleBlocked
+	// roleSkipped roles are reported but do not affect the verdict, e.g.
+	// node types that have no Graviton option at all
+	roleSkipped
+)
+
+// roleFinding is the Graviton decision for one part of a resource, such as a
+// node role of a domain or an instance group of a cluster.
+type roleFinding struct {
+	Role string
+	// Current describes what the role uses today, e.g. its instance type
+	Current string
+	// Attribute is the state path of the instance type the role uses
+	Attribute      string
+	Status         roleStatus
+	Recommendation string
+	// Missing is set when the part the role refers to is not in the state
+	Missing bool
+	Message string
+	// Unverified lists evidence the decision depends on that could not be
+	// inspected, such as container images
+	Unverified []string
+}
+
+// applyRoleFindings derives a resource verdict from its per-role findings: the
+// resource only counts as using ARM64 once every role does, and is blocked if
+// any role has no ARM64 option. unit names the roles in the summary finding.
+func applyRoleFindings(analysis *ARM64Analysis, findings []roleFinding, unit string) {
+	applyRoleVerdict(analysis, findings, unit, false)
+}
+
+// applyIndependentRoleFindings is applyRoleFindings for roles that can move to
+// ARM64 independently of each other, such as the variants of a SageMaker
+// endpoint: the resource is partially migratable when some roles can move
+// while others have no ARM64 option.
+func applyIndependentRoleFindings(analysis *ARM64Analysis, findings []roleFinding, unit string) {
+	applyRoleVerdict(analysis, findings, unit, true)
+}
+
+func applyRoleVerdict(analysis *ARM64Analysis, findings []roleFinding, unit string, independent bool) {
+	var recommendations, blocked []string
+	var eligible, graviton int
+	for _, finding := range findings {
+		switch finding.Status {
+		case roleSkipped:
+			continue
+		case roleGraviton:
+			graviton++
+		case roleMigratable:
+			recommendations = append(recommendations, finding.Role+": "+finding.Recommendation)
+		case roleBlocked:
+			blocked = append(blocked, finding.Role)
+		}
+		eligible++
+	}
+
+	switch {
+	case eligible == 0:
+		markUnknown(analysis, "", "No "+unit+" with a known ARM64 option")
+	case graviton == eligible:
+		analysis.setArchitecture(ArchitectureARM64)
+		analysis.RecommendedArch = "ARM64"
+		analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "", "Already using ARM64 for all "+unit)
+	case independent && len(blocked) > 0 && len(recommendations) > 0:
+		analysis.RecommendedArch = strings.Join(recommendations, ", ")
+		analysis.decide(StatusMigratable, FindingMigratable, "", fmt.Sprintf("Partially migratable: %d of %d %s can use ARM64; blocked: %s",
+			graviton+len(recommendations), eligible, unit, strings.Join(blocked, ", ")))
+	case len(blocked) > 0:
+		analysis.RecommendedArch = ""
+		if graviton > 0 {
+			analysis.setArchitecture(ArchitectureMixed)
+		}
+		analysis.decide(StatusBlocked, FindingNoARM64Option, "", "No ARM64 option for "+strings.Join(blocked, ", "))
+	default:
+		analysis.RecommendedArch = strings.Join(recommendations, ", ")
+		if graviton > 0 {
+			analysis.setArchitecture(ArchitectureMixed)
+			analysis.decide(StatusPartiallyMigrated, FindingPartiallyMigrated, "", fmt.Sprintf("Partially migrated: %d of %d %s use ARM64", graviton, eligible, unit))
+		} else {
+			analysis.decide(StatusMigratable, FindingMigratable, "", "Can migrate "+unit+" to ARM64")
+		}
+	}
+
+	for _, finding := range findings {
+		label := finding.Role
+		if finding.Current != "" {
+			label += " (" + finding.Current + ")"
+		}
+		code := FindingNote
+		switch {
+		case finding.Status == roleBlocked:
+			code = FindingNoARM64Option
+		case finding.Missing:
+			code = FindingMissingAttribute
+		}
+		analysis.addFinding(code, finding.Attribute, label+": "+finding.Message)
+		for _, message := range finding.Unverified {
+			analysis.addFinding(FindingUnverified, finding.Attribute, message)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package analyzer
+
+import "testing"
+
+func TestApplyRoleFindings_BlockedRole(t *testing.T) {
+	findings := []roleFinding{
+		{Role: "data", Status: roleMigratable, Recommendation: "r7g.large.search", Message: "can migrate to r7g.large.search"},
+		{Role: "warm", Status: roleBlocked, Message: "no ARM64 option"},
+	}
+
+	var shared ARM64Analysis
+	applyRoleFindings(&shared, findings, "node roles")
+	if shared.Status != StatusBlocked {
+		t.Errorf("applyRoleFindings() Status = %v, want %v", shared.Status, StatusBlocked)
+	}
+
+	var independent ARM64Analysis
+	applyIndependentRoleFindings(&independent, findings, "variants")
+	if independent.Status != StatusMigratable || independent.RecommendedArch != "data: r7g.large.search" {
+		t.Errorf("applyIndependentRoleFindings() Status = %v, RecommendedArch = %q, want migratable data: r7g.large.search", independent.Status, independent.RecommendedArch)
+	}
+}
@@ -1,8 +1,16 @@
 package analyzer
 
-import "github.com/suer/tf-arm/internal/parser"
+import (
+	"fmt"
+	"slices"
+	"strings"
 
-type SageMakerAnalyzer struct{}
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+type SageMakerAnalyzer struct {
+	ctx *Context
+}
 
 func (a *SageMakerAnalyzer) SupportedType() string {
 	return "aws_sagemaker_endpoint_configuration"
@@ -13,48 +21,148 @@ func (a *SageMakerAnalyzer) Analyze(resource parser.TerraformResource) ARM64Anal
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
 		ARM64Compatible: false,
-		CurrentArch:     "X86_64",
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
-		if productionVariants, exists := instance.Attributes["production_variants"]; exists {
-			variantsList, ok := productionVariants.([]any)
+		var findings []roleFinding
+		serverlessOnly := true
+		for _, attribute := range []string{"production_variants", "shadow_production_variants"} {
+			variants, ok := instance.Attributes[attribute].([]any)
 			if !ok {
 				continue
 			}
-			if len(variantsList) > 0 {
-				variant, ok := variantsList[0].(map[string]any)
+			for i, variant := range variants {
+				variantMap, ok := variant.(map[string]any)
 				if !ok {
 					continue
 				}
+				serverless, _ := variantMap["serverless_config"].([]any)
+				serverlessOnly = serverlessOnly && len(serverless) > 0
+				findings = append(findings, a.analyzeVariant(attribute, i, variantMap))
+			}
+		}
+		if len(findings) == 0 {
+			continue
+		}
+		if serverlessOnly {
+			analysis.setArchitecture(ArchitectureManaged)
+			analysis.decide(StatusNotApplicable, FindingNotApplicable, "production_variants", "all variants use serverless inference, whose capacity is managed by AWS")
+			continue
+		}
+		applyIndependentRoleFindings(&analysis, findings, "variants")
+	}
+	return analysis
+}
+
+// analyzeVariant makes the Graviton decision for one production or shadow
+// variant, including whether its model container has an arm64 image.
+func (a *SageMakerAnalyzer) analyzeVariant(attribute string, index int, variant map[string]any) roleFinding {
+	name, _ := variant["variant_name"].(string)
+	if name == "" {
+		name = fmt.Sprintf("#%d", index)
+	}
+	role := "variant " + name
+	if attribute == "shadow_production_variants" {
+		role = "shadow variant " + name
+	}
+	finding := roleFinding{Role: role, Attribute: fmt.Sprintf("%s.%d.instance_type", attribute, index)}
 
-				if instanceType, exists := variant["instance_type"]; exists {
-					instanceTypeStr, ok := instanceType.(string)
+	if serverless, ok := variant["serverless_config"].([]any); ok && len(serverless) > 0 {
+		finding.Current = "serverless"
+		finding.Status = roleSkipped
+		finding.Message = "serverless inference capacity is managed by AWS"
+		return finding
+	}
+
+	instanceType, _ := variant["instance_type"].(string)
+	finding.Current = instanceType
+	switch {
+	case instanceType == "":
+		finding.Status = roleSkipped
+		finding.Message = "instance_type not in state"
+		return finding
+	case isARM64SageMakerInstanceType(instanceType):
+		finding.Status = roleGraviton
+		finding.Message = "already using ARM64"
+	case hasARM64SageMakerAlternative(instanceType):
+		finding.Status = roleMigratable
+		finding.Recommendation = getARM64SageMakerAlternative(instanceType)
+		finding.Message = "can migrate to " + finding.Recommendation
+	default:
+		finding.Status = roleBlocked
+		finding.Message = "no ARM64 compatible instance type available"
+		return finding
+	}
+
+	modelName, _ := variant["model_name"].(string)
+	for _, image := range a.findModelImages(modelName) {
+		switch getSageMakerImageArch(a.ctx, image) {
+		case ArchitectureX86_64:
+			finding.Status = roleBlocked
+			finding.Recommendation = ""
+			finding.Message = "model " + modelName + " container " + image + " is x86-only"
+			finding.Unverified = nil
+			return finding
+		case ArchitectureUnknown:
+			finding.Unverified = append(finding.Unverified, "verify model "+modelName+" container "+image+" has an arm64 image")
+		}
+	}
+	return finding
+}
+
+// findModelImages returns the container images of the aws_sagemaker_model
+// with the given name.
+func (a *SageMakerAnalyzer) findModelImages(modelName string) []string {
+	if modelName == "" {
+		return nil
+	}
+
+	var images []string
+	for _, model := range a.ctx.FindResources("aws_sagemaker_model") {
+		for _, instance := range model.Instances {
+			if name, _ := instance.Attributes["name"].(string); name != modelName {
+				continue
+			}
+			for _, attribute := range []string{"primary_container", "container"} {
+				containers, ok := instance.Attributes[attribute].([]any)
+				if !ok {
+					continue
+				}
+				for _, container := range containers {
+					containerMap, ok := container.(map[string]any)
 					if !ok {
 						continue
 					}
-
-					if isARM64SageMakerInstanceType(instanceTypeStr) {
-						analysis.ARM64Compatible = true
-						analysis.CurrentArch = "ARM64"
-						analysis.RecommendedArch = "ARM64"
-						analysis.AlreadyUsingARM64 = true
-						analysis.Notes = "Already using ARM64 instance type"
-					} else if hasARM64SageMakerAlternative(instanceTypeStr) {
-						analysis.ARM64Compatible = true
-						analysis.RecommendedArch = getARM64SageMakerAlternative(instanceTypeStr)
-						analysis.Notes = "Can migrate to ARM64 instance type: " + analysis.RecommendedArch
-					} else {
-						analysis.Notes = "No ARM64 compatible instance type available"
+					if image, ok := containerMap["image"].(string); ok && image != "" {
+						images = append(images, image)
 					}
 				}
 			}
 		}
 	}
-	return analysis
+	return images
+}
+
+// getSageMakerImageArch infers a model image's architecture from the image
+// cache or the Graviton tags used by AWS Deep Learning Containers, returning
+// ArchitectureUnknown when it cannot be determined.
+func getSageMakerImageArch(ctx *Context, image string) Architecture {
+	if architectures, found := ctx.ImageArchitectures(image); found {
+		if slices.Contains(architectures, "arm64") {
+			return ArchitectureARM64
+		}
+		return ArchitectureX86_64
+	}
+	if strings.Contains(image, "graviton") || strings.Contains(image, "arm64") {
+		return ArchitectureARM64
+	}
+	return ArchitectureUnknown
 }
 
-type GameLiftAnalyzer struct{}
+type GameLiftAnalyzer struct {
+	ctx *Context
+}
 
 func (a *GameLiftAnalyzer) SupportedType() string {
 	return "aws_gamelift_fleet"
@@ -65,10 +173,15 @@ func (a *GameLiftAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analy
 		ResourceType:    resource.Type,
 		ResourceName:    resource.Name,
 		ARM64Compatible: false,
-		CurrentArch:     "X86_64",
 	}
+	analysis.setArchitecture(ArchitectureX86_64)
 
 	for _, instance := range resource.Instances {
+		if operatingSystem := a.getOperatingSystem(instance.Attributes); isWindowsOperatingSystem(operatingSystem) {
+			markNotApplicableWindows(&analysis, operatingSystem)
+			continue
+		}
+
 		if ec2InstanceType, exists := instance.Attributes["ec2_instance_type"]; exists {
 			instanceTypeStr, ok := ec2InstanceType.(string)
 			if !ok {
@@ -76,23 +189,44 @@ func (a *GameLiftAnalyzer) Analyze(resource parser.TerraformResource) ARM64Analy
 			}
 
 			if isARM64GameLiftInstanceType(instanceTypeStr) {
-				analysis.ARM64Compatible = true
-				analysis.CurrentArch = "ARM64"
+				analysis.setArchitecture(ArchitectureARM64)
 				analysis.RecommendedArch = "ARM64"
-				analysis.AlreadyUsingARM64 = true
-				analysis.Notes = "Already using ARM64 instance type"
+				analysis.decide(StatusAlreadyARM64, FindingAlreadyARM64, "ec2_instance_type", "Already using ARM64 instance type")
 			} else if hasARM64GameLiftAlternative(instanceTypeStr) {
-				analysis.ARM64Compatible = true
+				analysis.setArchitecture(ArchitectureX86_64)
 				analysis.RecommendedArch = getARM64GameLiftAlternative(instanceTypeStr)
-				analysis.Notes = "Can migrate to ARM64 instance type: " + analysis.RecommendedArch
+				analysis.decide(StatusMigratable, FindingMigratable, "ec2_instance_type", "Can migrate to ARM64 instance type: "+analysis.RecommendedArch)
 			} else {
-				analysis.Notes = "GameLift supports ARM64 with Graviton2 instances"
+				analysis.setArchitecture(ArchitectureX86_64)
+				analysis.decide(StatusBlocked, FindingNoARM64Option, "ec2_instance_type", "No ARM64 compatible instance type available for "+instanceTypeStr)
 			}
 		}
 	}
 	return analysis
 }
 
+// getOperatingSystem returns the fleet's operating system, falling back to
+// the operating_system of the aws_gamelift_build referenced by build_id.
+func (a *GameLiftAnalyzer) getOperatingSystem(attributes map[string]any) string {
+	if operatingSystem, ok := attributes["operating_system"].(string); ok && operatingSystem != "" {
+		return operatingSystem
+	}
+
+	buildID, ok := attributes["build_id"].(string)
+	if !ok || buildID == "" {
+		return ""
+	}
+	for _, build := range a.ctx.FindResources("aws_gamelift_build") {
+		for _, instance := range build.Instances {
+			if id, _ := instance.Attributes["id"].(string); id == buildID {
+				operatingSystem, _ := instance.Attributes["operating_system"].(string)
+				return operatingSystem
+			}
+		}
+	}
+	return ""
+}
+
 func isARM64SageMakerInstanceType(instanceType string) bool {
 	arm64Types := []string{
 		// Graviton2
@@ -0,0 +1,190 @@
+package analyzer
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestSageMakerAnalyzer_Analyze(t *testing.T) {
+	model := func(name, image string) parser.TerraformResource {
+		return parser.TerraformResource{
+			Mode: "managed",
+			Type: "aws_sagemaker_model",
+			Name: name,
+			Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+				"name":              name,
+				"primary_container": []any{map[string]any{"image": image}},
+			}}},
+		}
+	}
+	instanceVariant := func(name, instanceType, modelName string) map[string]any {
+		return map[string]any{"variant_name": name, "instance_type": instanceType, "model_name": modelName}
+	}
+	serverlessVariant := func(name string) map[string]any {
+		return map[string]any{
+			"variant_name":      name,
+			"model_name":        "app",
+			"serverless_config": []any{map[string]any{"max_concurrency": float64(5), "memory_size_in_mb": float64(2048)}},
+		}
+	}
+
+	ctx := NewContext(&parser.TerraformState{Version: 4, Resources: []parser.TerraformResource{
+		model("app", "registry.example.com/app:1.0"),
+		model("legacy", "registry.example.com/legacy:1.0"),
+		model("unknown", "registry.example.com/unknown:1.0"),
+	}})
+	ctx.Images = newTestImageCache(t, map[string][]string{
+		"registry.example.com/app:1.0":    {"amd64", "arm64"},
+		"registry.example.com/legacy:1.0": {"amd64"},
+	})
+
+	tests := []struct {
+		name          string
+		variants      []any
+		shadow        []any
+		expectStatus  Status
+		expectArch    Architecture
+		expectCodes   []FindingCode
+		expectMessage string
+	}{
+		{
+			name:         "instance variant with a multi-arch model image",
+			variants:     []any{instanceVariant("primary", "ml.m5.large", "app")},
+			expectStatus: StatusMigratable,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingMigratable, FindingNote},
+		},
+		{
+			name:         "Graviton instance variant",
+			variants:     []any{instanceVariant("primary", "ml.c6g.large", "app")},
+			expectStatus: StatusAlreadyARM64,
+			expectArch:   ArchitectureARM64,
+			expectCodes:  []FindingCode{FindingAlreadyARM64, FindingNote},
+		},
+		{
+			name:         "serverless variants only",
+			variants:     []any{serverlessVariant("primary")},
+			shadow:       []any{serverlessVariant("shadow")},
+			expectStatus: StatusNotApplicable,
+			expectArch:   ArchitectureManaged,
+			expectCodes:  []FindingCode{FindingNotApplicable},
+		},
+		{
+			name:          "serverless and instance variants",
+			variants:      []any{serverlessVariant("primary")},
+			shadow:        []any{instanceVariant("shadow", "ml.m5.large", "app")},
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote, FindingNote},
+			expectMessage: "shadow variant shadow (ml.m5.large): can migrate to ml.m7g.large",
+		},
+		{
+			name: "variant whose model image is x86-only",
+			variants: []any{
+				instanceVariant("primary", "ml.m5.large", "app"),
+				instanceVariant("canary", "ml.m5.large", "legacy"),
+			},
+			expectStatus:  StatusMigratable,
+			expectArch:    ArchitectureX86_64,
+			expectCodes:   []FindingCode{FindingMigratable, FindingNote, FindingNoARM64Option},
+			expectMessage: "Partially migratable: 1 of 2 variants can use ARM64; blocked: variant canary",
+		},
+		{
+			name:         "model image not in the image cache",
+			variants:     []any{instanceVariant("primary", "ml.m5.large", "unknown")},
+			expectStatus: StatusMigratable,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingMigratable, FindingNote, FindingUnverified},
+		},
+		{
+			name:         "GPU variant without a Graviton option",
+			variants:     []any{instanceVariant("primary", "ml.p3.2xlarge", "app")},
+			expectStatus: StatusBlocked,
+			expectArch:   ArchitectureX86_64,
+			expectCodes:  []FindingCode{FindingNoARM64Option, FindingNoARM64Option},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attributes := map[string]any{"production_variants": tt.variants}
+			if tt.shadow != nil {
+				attributes["shadow_production_variants"] = tt.shadow
+			}
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Mode:      "managed",
+				Type:      "aws_sagemaker_endpoint_configuration",
+				Name:      "endpoint",
+				Instances: []parser.ResourceInstance{{Attributes: attributes}},
+			}, ctx)
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v (notes: %s)", analysis.Status, tt.expectStatus, analysis.Notes)
+			}
+			if analysis.Architecture != tt.expectArch {
+				t.Errorf("Architecture = %v, want %v", analysis.Architecture, tt.expectArch)
+			}
+			if codes := getFindingCodes(analysis); !slices.Equal(codes, tt.expectCodes) {
+				t.Errorf("finding codes = %v, want %v", codes, tt.expectCodes)
+			}
+			if tt.expectMessage != "" && !slices.ContainsFunc(analysis.Findings, func(finding Finding) bool { return finding.Message == tt.expectMessage }) {
+				t.Errorf("Findings = %+v, want message %q", analysis.Findings, tt.expectMessage)
+			}
+		})
+	}
+}
+
+func TestGameLiftAnalyzer_Analyze(t *testing.T) {
+	tests := []struct {
+		name          string
+		instanceType  string
+		expectStatus  Status
+		expectCodes   []FindingCode
+		expectMessage string
+	}{
+		{
+			name:          "Graviton fleet",
+			instanceType:  "c6g.large",
+			expectStatus:  StatusAlreadyARM64,
+			expectCodes:   []FindingCode{FindingAlreadyARM64},
+			expectMessage: "Already using ARM64 instance type",
+		},
+		{
+			name:          "x86 fleet with a Graviton alternative",
+			instanceType:  "c5.large",
+			expectStatus:  StatusMigratable,
+			expectCodes:   []FindingCode{FindingMigratable},
+			expectMessage: "Can migrate to ARM64 instance type: c7g.large",
+		},
+		{
+			name:          "x86 fleet without a Graviton alternative",
+			instanceType:  "p3.2xlarge",
+			expectStatus:  StatusBlocked,
+			expectCodes:   []FindingCode{FindingNoARM64Option},
+			expectMessage: "No ARM64 compatible instance type available for p3.2xlarge",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResourceWithContext(parser.TerraformResource{
+				Mode:      "managed",
+				Type:      "aws_gamelift_fleet",
+				Name:      "fleet",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{"ec2_instance_type": tt.instanceType}}},
+			}, NewContext(&parser.TerraformState{Version: 4}))
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v (notes: %s)", analysis.Status, tt.expectStatus, analysis.Notes)
+			}
+			if codes := getFindingCodes(analysis); !slices.Equal(codes, tt.expectCodes) {
+				t.Errorf("finding codes = %v, want %v", codes, tt.expectCodes)
+			}
+			if analysis.reason() != tt.expectMessage {
+				t.Errorf("reason = %q, want %q", analysis.reason(), tt.expectMessage)
+			}
+		})
+	}
+}
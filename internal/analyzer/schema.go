@@ -0,0 +1,185 @@
+package analyzer
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+// attributePath is one place a logical attribute can live in a resource's
+// state. Nested blocks are stored as lists, so paths index into them, e.g.
+// "runtime_platform.0.cpu_architecture".
+type attributePath struct {
+	Path string
+	// MinProviderMajor and MaxProviderMajor bound the provider major versions
+	// that write this path; zero means unbounded.
+	MinProviderMajor int
+	MaxProviderMajor int
+}
+
+func (p attributePath) appliesTo(providerMajor int) bool {
+	if providerMajor == 0 {
+		return true
+	}
+	if p.MinProviderMajor != 0 && providerMajor < p.MinProviderMajor {
+		return false
+	}
+	if p.MaxProviderMajor != 0 && providerMajor > p.MaxProviderMajor {
+		return false
+	}
+	return true
+}
+
+// getAttributeSchemas declares, per resource type, where each logical
+// attribute an analyzer reads is stored across provider major versions.
+// Paths are listed newest first.
+func getAttributeSchemas() map[string]map[string][]attributePath {
+	elastiCacheEngineVersion := []attributePath{
+		// engine_version_actual holds the full version whenever the provider
+		// writes it; engine_version may only be a major version such as "7.0"
+		// or "6.x"
+		{Path: "engine_version_actual"},
+		{Path: "engine_version"},
+	}
+
+	// RDS engine_version holds the configured version, which may be a major
+	// version such as "8.0" when auto_minor_version_upgrade is used
+	rdsEngineVersion := []attributePath{
+		{Path: "engine_version_actual"},
+		{Path: "engine_version"},
+	}
+
+	return map[string]map[string][]attributePath{
+		"aws_db_instance": {
+			"engine_version": rdsEngineVersion,
+		},
+		"aws_rds_cluster": {
+			"engine_version": rdsEngineVersion,
+		},
+		"aws_rds_cluster_instance": {
+			"engine_version": rdsEngineVersion,
+		},
+		// DocumentDB and Neptune are RDS-family engines and read
+		// engine_version the same way
+		"aws_docdb_cluster": {
+			"engine_version": rdsEngineVersion,
+		},
+		"aws_docdb_cluster_instance": {
+			"engine_version": rdsEngineVersion,
+		},
+		"aws_neptune_cluster": {
+			"engine_version": rdsEngineVersion,
+		},
+		"aws_neptune_cluster_instance": {
+			"engine_version": rdsEngineVersion,
+		},
+		"aws_elasticache_cluster": {
+			"engine_version": elastiCacheEngineVersion,
+		},
+		"aws_elasticache_replication_group": {
+			"engine_version": elastiCacheEngineVersion,
+			// num_cache_clusters replaced number_cache_clusters in AWS provider
+			// v4, which still accepts the deprecated name; v5 removed it
+			"num_cache_clusters": {
+				{Path: "num_cache_clusters", MinProviderMajor: 4},
+				{Path: "number_cache_clusters", MaxProviderMajor: 4},
+			},
+		},
+		// The resource was added in AWS provider v3.12 with
+		// engine_version_actual; engine_version became an argument later in v3
+		"aws_elasticache_global_replication_group": {
+			"engine_version": elastiCacheEngineVersion,
+		},
+		"aws_ecs_task_definition": {
+			// runtime_platform was added in AWS provider v3.70
+			"cpu_architecture": {
+				{Path: "runtime_platform.0.cpu_architecture", MinProviderMajor: 3},
+			},
+			"operating_system_family": {
+				{Path: "runtime_platform.0.operating_system_family", MinProviderMajor: 3},
+			},
+		},
+	}
+}
+
+// lookupAttribute resolves a logical attribute through the schema declared
+// for the resource type, returning the value and the path it was found at.
+// Attributes without a declared schema are read from the top level.
+func (c *Context) lookupAttribute(resource parser.TerraformResource, attributes map[string]any, name string) (any, string, bool) {
+	paths, declared := getAttributeSchemas()[resource.Type][name]
+	if !declared {
+		value, exists := attributes[name]
+		return value, name, exists
+	}
+
+	providerMajor := c.ProviderMajor(resource)
+	for _, path := range paths {
+		if !path.appliesTo(providerMajor) {
+			continue
+		}
+		// Computed attributes that were never populated are stored as null or
+		// an empty string, so fall through to older paths
+		if value, exists := getAttributePath(attributes, path.Path); exists && value != nil && value != "" {
+			return value, path.Path, true
+		}
+	}
+	return nil, "", false
+}
+
+// attributeAvailable reports whether the configured provider version can
+// write the attribute at all. Unknown provider versions are assumed to.
+func (c *Context) attributeAvailable(resource parser.TerraformResource, name string) bool {
+	paths, declared := getAttributeSchemas()[resource.Type][name]
+	if !declared {
+		return true
+	}
+	providerMajor := c.ProviderMajor(resource)
+	for _, path := range paths {
+		if path.appliesTo(providerMajor) {
+			return true
+		}
+	}
+	return false
+}
+
+// ProviderMajor returns the configured major version of the provider that
+// manages the resource, or zero when it is unknown.
+func (c *Context) ProviderMajor(resource parser.TerraformResource) int {
+	if c == nil {
+		return 0
+	}
+	return c.ProviderVersions[resource.GetProviderName()]
+}
+
+// ParseProviderMajor extracts the major version from a provider version
+// string such as "5.31.0", "v4" or "~> 5.0".
+func ParseProviderMajor(version string) (int, error) {
+	version = strings.TrimLeft(strings.TrimSpace(version), "~>=<! v")
+	major, _, _ := strings.Cut(version, ".")
+	return strconv.Atoi(major)
+}
+
+// getAttributePath walks a dotted path through nested maps and lists.
+func getAttributePath(attributes map[string]any, path string) (any, bool) {
+	var current any = attributes
+	for _, segment := range strings.Split(path, ".") {
+		switch node := current.(type) {
+		case map[string]any:
+			value, exists := node[segment]
+			if !exists {
+				return nil, false
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current = node[index]
+		default:
+			return nil, false
+		}
+	}
+	return current, true
+}
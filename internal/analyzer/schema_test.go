@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestGetAttributePath(t *testing.T) {
+	attributes := map[string]any{
+		"runtime_platform": []any{
+			map[string]any{"cpu_architecture": "ARM64"},
+		},
+		"name": "task",
+	}
+
+	tests := []struct {
+		path        string
+		expected    any
+		expectFound bool
+	}{
+		{"runtime_platform.0.cpu_architecture", "ARM64", true},
+		{"name", "task", true},
+		{"runtime_platform.1.cpu_architecture", nil, false},
+		{"runtime_platform.x.cpu_architecture", nil, false},
+		{"name.0", nil, false},
+		{"missing", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			value, found := getAttributePath(attributes, tt.path)
+			if found != tt.expectFound || value != tt.expected {
+				t.Errorf("getAttributePath(%q) = %v, %v, want %v, %v", tt.path, value, found, tt.expected, tt.expectFound)
+			}
+		})
+	}
+}
+
+func TestParseProviderMajor(t *testing.T) {
+	tests := []struct {
+		version   string
+		expected  int
+		expectErr bool
+	}{
+		{"5.31.0", 5, false},
+		{"v4", 4, false},
+		{"~> 3.70", 3, false},
+		{">= 5.0", 5, false},
+		{"latest", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			major, err := ParseProviderMajor(tt.version)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ParseProviderMajor(%q) error = %v, expectErr %v", tt.version, err, tt.expectErr)
+			}
+			if major != tt.expected {
+				t.Errorf("ParseProviderMajor(%q) = %d, want %d", tt.version, major, tt.expected)
+			}
+		})
+	}
+}
+
+func TestECSAnalyzer_RuntimePlatform(t *testing.T) {
+	tests := []struct {
+		name          string
+		attributes    map[string]interface{}
+		providerMajor int
+		expectArch    string
+		expectUsing   bool
+		expectNotes   string
+	}{
+		{
+			name: "ARM64 runtime_platform",
+			attributes: map[string]interface{}{
+				"runtime_platform": []any{
+					map[string]any{"cpu_architecture": "ARM64", "operating_system_family": "LINUX"},
+				},
+			},
+			expectArch:  "ARM64",
+			expectUsing: true,
+			expectNotes: "Already using ARM64 architecture",
+		},
+		{
+			name: "X86_64 runtime_platform",
+			attributes: map[string]interface{}{
+				"runtime_platform": []any{
+					map[string]any{"cpu_architecture": "X86_64"},
+				},
+			},
+			providerMajor: 5,
+			expectArch:    "X86_64",
+			expectNotes:   "Can set runtime_platform { cpu_architecture = \"ARM64\" }",
+		},
+		{
+			name:        "no runtime_platform",
+			attributes:  map[string]interface{}{},
+			expectArch:  "X86_64 (default)",
+			expectNotes: "Can add runtime_platform",
+		},
+		{
+			name:          "provider too old for runtime_platform",
+			attributes:    map[string]interface{}{},
+			providerMajor: 2,
+			expectArch:    "X86_64 (default)",
+			expectNotes:   "Upgrade the AWS provider",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := &Context{ProviderVersions: map[string]int{"aws": tt.providerMajor}}
+			analyzer := &ECSAnalyzer{ctx: ctx}
+			analysis := analyzer.Analyze(parser.TerraformResource{
+				Type:      "aws_ecs_task_definition",
+				Name:      "task",
+				Provider:  `provider["registry.terraform.io/hashicorp/aws"]`,
+				Instances: []parser.ResourceInstance{{Attributes: tt.attributes}},
+			})
+
+			if analysis.CurrentArch != tt.expectArch {
+				t.Errorf("Analyze() CurrentArch = %v, want %v", analysis.CurrentArch, tt.expectArch)
+			}
+			if analysis.AlreadyUsingARM64 != tt.expectUsing {
+				t.Errorf("Analyze() AlreadyUsingARM64 = %v, want %v", analysis.AlreadyUsingARM64, tt.expectUsing)
+			}
+			if !strings.Contains(analysis.Notes, tt.expectNotes) {
+				t.Errorf("Analyze() Notes = %q, want to contain %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
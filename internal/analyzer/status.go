@@ -0,0 +1,218 @@
+package analyzer
+
+// Status is the migration state of a resource.
+type Status string
+
+const (
+	StatusAlreadyARM64      Status = "already_arm64"
+	StatusMigratable        Status = "migratable"
+	StatusPartiallyMigrated Status = "partially_migrated"
+	// StatusBlocked resources have no ARM64 option today, e.g. an instance
+	// family without a Graviton equivalent or an image without an arm64
+	// variant.
+	StatusBlocked       Status = "blocked"
+	StatusMisconfigured Status = "misconfigured"
+	// StatusUnknown resources lack the attributes, or the referenced
+	// resources, needed to decide.
+	StatusUnknown       Status = "unknown"
+	StatusNotApplicable Status = "not_applicable"
+	StatusUnsupported   Status = "unsupported"
+)
+
+// Architecture is the CPU architecture a resource runs on.
+type Architecture string
+
+const (
+	ArchitectureX86_64 Architecture = "x86_64"
+	ArchitectureARM64  Architecture = "arm64"
+	ArchitectureMixed  Architecture = "mixed"
+	// ArchitectureAny resources run wherever they are scheduled, such as
+	// Kubernetes workloads that do not pin kubernetes.io/arch
+	ArchitectureAny Architecture = "any"
+	// ArchitectureManaged capacity is chosen by AWS, e.g. serverless databases
+	ArchitectureManaged Architecture = "managed"
+	// ArchitectureExternal resources run on machines outside the cloud
+	// provider, e.g. ECS Anywhere
+	ArchitectureExternal Architecture = "external"
+	ArchitectureUnknown  Architecture = "unknown"
+)
+
+// Severity ranks findings: errors explain why a resource cannot migrate,
+// warnings are prerequisites or gaps in the evidence, and info findings
+// describe the current state and recommendation.
+type Severity string
+
+const (
+	SeverityInfo    Severity = "info"
+	SeverityWarning Severity = "warning"
+	SeverityError   Severity = "error"
+)
+
+// FindingCode identifies the kind of a finding for tooling.
+type FindingCode string
+
+const (
+	FindingAlreadyARM64      FindingCode = "already_arm64"
+	FindingMigratable        FindingCode = "migratable"
+	FindingPartiallyMigrated FindingCode = "partially_migrated"
+	// FindingNoARM64Option is an instance type, class or size without an
+	// ARM64 equivalent
+	FindingNoARM64Option FindingCode = "no_arm64_option"
+	// FindingBlocked is anything else that rules ARM64 out, such as a
+	// runtime, an engine version or an attached accelerator
+	FindingBlocked       FindingCode = "blocked"
+	FindingMisconfigured FindingCode = "misconfigured"
+	FindingNotApplicable FindingCode = "not_applicable"
+	FindingPrerequisite  FindingCode = "prerequisite"
+	// FindingMissingAttribute is an attribute, or a referenced resource,
+	// missing from the state
+	FindingMissingAttribute FindingCode = "missing_attribute"
+	// FindingUnverified is something the recommendation depends on that the
+	// analyzer could not inspect, such as a layer or a container image
+	FindingUnverified       FindingCode = "unverified"
+	FindingOrphanedCapacity FindingCode = "orphaned_capacity"
+	FindingNote             FindingCode = "note"
+)
+
+// Finding is one structured observation about a resource. Attribute is the
+// state path the finding is about, e.g. "node_config.0.machine_type", when
+// there is one.
+type Finding struct {
+	Code      FindingCode
+	Severity  Severity
+	Message   string
+	Attribute string
+}
+
+// getFindingSeverities returns the severity of each finding code.
+func getFindingSeverities() map[FindingCode]Severity {
+	return map[FindingCode]Severity{
+		FindingAlreadyARM64:      SeverityInfo,
+		FindingMigratable:        SeverityInfo,
+		FindingPartiallyMigrated: SeverityInfo,
+		FindingNoARM64Option:     SeverityError,
+		FindingBlocked:           SeverityError,
+		FindingMisconfigured:     SeverityError,
+		FindingNotApplicable:     SeverityInfo,
+		FindingPrerequisite:      SeverityWarning,
+		FindingMissingAttribute:  SeverityWarning,
+		FindingUnverified:        SeverityWarning,
+		FindingOrphanedCapacity:  SeverityWarning,
+		FindingNote:              SeverityInfo,
+	}
+}
+
+// getFindingLabels returns the labels that prefix findings in the legacy
+// Notes field.
+func getFindingLabels() map[FindingCode]string {
+	return map[FindingCode]string{
+		FindingBlocked:          "Blocked: ",
+		FindingMisconfigured:    "Misconfigured: ",
+		FindingNotApplicable:    "Not applicable: ",
+		FindingPrerequisite:     "Prerequisite: ",
+		FindingOrphanedCapacity: "Would be orphaned: ",
+	}
+}
+
+// getLegacyArchitectures returns the CurrentArch value of each architecture.
+func getLegacyArchitectures() map[Architecture]string {
+	return map[Architecture]string{
+		ArchitectureX86_64:   "X86_64",
+		ArchitectureARM64:    "ARM64",
+		ArchitectureMixed:    "Mixed",
+		ArchitectureAny:      "Any",
+		ArchitectureManaged:  "Serverless",
+		ArchitectureExternal: "External",
+	}
+}
+
+func newFinding(code FindingCode, attribute, message string) Finding {
+	return Finding{
+		Code:      code,
+		Severity:  getFindingSeverities()[code],
+		Message:   message,
+		Attribute: attribute,
+	}
+}
+
+// decide records the verdict of an analysis: its status and the finding that
+// explains it. Earlier findings are discarded, so the last instance of a
+// resource decides, and the legacy fields are rendered from the verdict.
+func (a *ARM64Analysis) decide(status Status, code FindingCode, attribute, message string) {
+	a.Status = status
+	a.ARM64Compatible = status == StatusAlreadyARM64 || status == StatusMigratable || status == StatusPartiallyMigrated
+	a.AlreadyUsingARM64 = status == StatusAlreadyARM64
+	a.NotApplicable = status == StatusNotApplicable
+	a.Findings = nil
+	a.Notes = ""
+	a.addFinding(code, attribute, message)
+}
+
+// addFinding records a finding in addition to the verdict.
+func (a *ARM64Analysis) addFinding(code FindingCode, attribute, message string) {
+	a.recordFinding(newFinding(code, attribute, message))
+}
+
+// recordFinding appends a finding and renders it into the legacy Notes.
+func (a *ARM64Analysis) recordFinding(finding Finding) {
+	a.Findings = append(a.Findings, finding)
+	if a.Notes != "" {
+		a.Notes += " | "
+	}
+	a.Notes += getFindingLabels()[finding.Code] + finding.Message
+}
+
+// setArchitecture records the architecture a resource runs on and renders the
+// legacy CurrentArch.
+func (a *ARM64Analysis) setArchitecture(architecture Architecture) {
+	a.Architecture = architecture
+	a.ArchitectureDefault = false
+	a.CurrentArch = getLegacyArchitectures()[architecture]
+}
+
+// setDefaultArchitecture records an architecture that comes from a provider
+// default rather than an attribute in the state.
+func (a *ARM64Analysis) setDefaultArchitecture(architecture Architecture) {
+	a.setArchitecture(architecture)
+	a.ArchitectureDefault = true
+	a.CurrentArch += " (default)"
+}
+
+// markUnknown records that an analyzer could not decide because attribute is
+// missing from the state or references a resource outside it.
+func markUnknown(analysis *ARM64Analysis, attribute, message string) {
+	analysis.decide(StatusUnknown, FindingMissingAttribute, attribute, message)
+}
+
+// adoptVerdict copies the verdict of a resource this one delegates to, such
+// as the image recipe of a pipeline. Its findings are attributed to the
+// attribute referencing it.
+func (a *ARM64Analysis) adoptVerdict(other ARM64Analysis, attribute string) {
+	a.Status = other.Status
+	a.ARM64Compatible = other.ARM64Compatible
+	a.AlreadyUsingARM64 = other.AlreadyUsingARM64
+	a.NotApplicable = other.NotApplicable
+	a.Architecture = other.Architecture
+	a.ArchitectureDefault = other.ArchitectureDefault
+	a.CurrentArch = other.CurrentArch
+	a.RecommendedArch = other.RecommendedArch
+	a.Findings = nil
+	a.Notes = ""
+	for _, finding := range other.Findings {
+		finding.Attribute = attribute
+		a.recordFinding(finding)
+	}
+}
+
+// reason returns the message of the finding that decided the verdict.
+func (a ARM64Analysis) reason() string {
+	if len(a.Findings) == 0 {
+		return ""
+	}
+	return a.Findings[0].Message
+}
+
+// canMigrate reports whether an analysis recommends moving to ARM64.
+func (a ARM64Analysis) canMigrate() bool {
+	return a.Status == StatusMigratable || a.Status == StatusPartiallyMigrated
+}
@@ -0,0 +1,206 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/suer/tf-arm/internal/parser"
+)
+
+func TestARM64Analysis_Decide(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         Status
+		code           FindingCode
+		expectSeverity Severity
+		expectARM64    bool
+		expectAlready  bool
+		expectNA       bool
+		expectNotes    string
+	}{
+		{"already using ARM64", StatusAlreadyARM64, FindingAlreadyARM64, SeverityInfo, true, true, false, "done"},
+		{"migratable", StatusMigratable, FindingMigratable, SeverityInfo, true, false, false, "done"},
+		{"partially migrated", StatusPartiallyMigrated, FindingPartiallyMigrated, SeverityInfo, true, false, false, "done"},
+		{"blocked", StatusBlocked, FindingBlocked, SeverityError, false, false, false, "Blocked: done"},
+		{"no ARM64 option", StatusBlocked, FindingNoARM64Option, SeverityError, false, false, false, "done"},
+		{"misconfigured", StatusMisconfigured, FindingMisconfigured, SeverityError, false, false, false, "Misconfigured: done"},
+		{"not applicable", StatusNotApplicable, FindingNotApplicable, SeverityInfo, false, false, true, "Not applicable: done"},
+		{"unknown", StatusUnknown, FindingMissingAttribute, SeverityWarning, false, false, false, "done"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := ARM64Analysis{Notes: "stale", Findings: []Finding{newFinding(FindingNote, "", "stale")}}
+			analysis.decide(tt.status, tt.code, "instance_type", "done")
+
+			if analysis.Status != tt.status {
+				t.Errorf("Status = %v, want %v", analysis.Status, tt.status)
+			}
+			if len(analysis.Findings) != 1 || analysis.Findings[0].Severity != tt.expectSeverity || analysis.Findings[0].Attribute != "instance_type" {
+				t.Errorf("Findings = %+v, want one %s finding on instance_type", analysis.Findings, tt.expectSeverity)
+			}
+			if analysis.ARM64Compatible != tt.expectARM64 || analysis.AlreadyUsingARM64 != tt.expectAlready || analysis.NotApplicable != tt.expectNA {
+				t.Errorf("ARM64Compatible, AlreadyUsingARM64, NotApplicable = %v, %v, %v, want %v, %v, %v",
+					analysis.ARM64Compatible, analysis.AlreadyUsingARM64, analysis.NotApplicable, tt.expectARM64, tt.expectAlready, tt.expectNA)
+			}
+			if analysis.Notes != tt.expectNotes {
+				t.Errorf("Notes = %q, want %q", analysis.Notes, tt.expectNotes)
+			}
+		})
+	}
+}
+
+func TestARM64Analysis_SetArchitecture(t *testing.T) {
+	tests := []struct {
+		architecture    Architecture
+		defaulted       bool
+		expectedCurrent string
+	}{
+		{ArchitectureX86_64, false, "X86_64"},
+		{ArchitectureX86_64, true, "X86_64 (default)"},
+		{ArchitectureARM64, false, "ARM64"},
+		{ArchitectureMixed, false, "Mixed"},
+		{ArchitectureManaged, false, "Serverless"},
+		{ArchitectureExternal, false, "External"},
+		{ArchitectureAny, false, "Any"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expectedCurrent, func(t *testing.T) {
+			var analysis ARM64Analysis
+			if tt.defaulted {
+				analysis.setDefaultArchitecture(tt.architecture)
+			} else {
+				analysis.setArchitecture(tt.architecture)
+			}
+			if analysis.Architecture != tt.architecture || analysis.ArchitectureDefault != tt.defaulted || analysis.CurrentArch != tt.expectedCurrent {
+				t.Errorf("got %v, %v, %q, want %v, %v, %q", analysis.Architecture, analysis.ArchitectureDefault, analysis.CurrentArch,
+					tt.architecture, tt.defaulted, tt.expectedCurrent)
+			}
+		})
+	}
+}
+
+func TestAnalyzeResource_Findings(t *testing.T) {
+	tests := []struct {
+		name         string
+		resource     parser.TerraformResource
+		expectStatus Status
+		expected     []Finding
+	}{
+		{
+			name: "misconfigured node group",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_eks_node_group",
+				Name: "mixed",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"ami_type":       "AL2_x86_64",
+					"instance_types": []any{"m5.large", "m7g.large"},
+				}}},
+			},
+			expectStatus: StatusMisconfigured,
+			expected: []Finding{
+				{Code: "misconfigured", Severity: SeverityError, Attribute: "instance_types"},
+			},
+		},
+		{
+			name: "GKE node pool",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "google_container_node_pool",
+				Name: "pool",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"node_config": []any{map[string]any{"machine_type": "n2-standard-4", "image_type": "UBUNTU"}},
+				}}},
+			},
+			expectStatus: StatusMigratable,
+			expected: []Finding{
+				{Code: "migratable", Severity: SeverityInfo, Attribute: "node_config.0.machine_type"},
+				{Code: "prerequisite", Severity: SeverityWarning, Attribute: "node_config.0.image_type"},
+				{Code: "note", Severity: SeverityInfo},
+			},
+		},
+		{
+			name: "RDS instance without engine version",
+			resource: parser.TerraformResource{
+				Mode: "managed",
+				Type: "aws_db_instance",
+				Name: "main",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{
+					"engine":         "mysql",
+					"instance_class": "db.t3.micro",
+				}}},
+			},
+			expectStatus: StatusMigratable,
+			expected: []Finding{
+				{Code: "migratable", Severity: SeverityInfo, Attribute: "instance_class"},
+				{Code: "missing_attribute", Severity: SeverityWarning, Attribute: "engine_version"},
+			},
+		},
+		{
+			name: "fleet without launch template config",
+			resource: parser.TerraformResource{
+				Mode:      "managed",
+				Type:      "aws_ec2_fleet",
+				Name:      "empty",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{}}},
+			},
+			expectStatus: StatusUnknown,
+			expected: []Finding{
+				{Code: "missing_attribute", Severity: SeverityWarning, Attribute: "launch_template_config"},
+			},
+		},
+		{
+			name: "instance without instance type",
+			resource: parser.TerraformResource{
+				Mode:      "managed",
+				Type:      "aws_instance",
+				Name:      "bare",
+				Instances: []parser.ResourceInstance{{Attributes: map[string]any{}}},
+			},
+			expectStatus: StatusUnknown,
+			expected: []Finding{
+				{Code: "missing_attribute", Severity: SeverityWarning},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis := AnalyzeResource(tt.resource)
+
+			if analysis.Status != tt.expectStatus {
+				t.Errorf("Status = %v, want %v (notes: %s)", analysis.Status, tt.expectStatus, analysis.Notes)
+			}
+			if len(analysis.Findings) != len(tt.expected) {
+				t.Fatalf("Findings = %+v, want %d findings", analysis.Findings, len(tt.expected))
+			}
+			for i, expected := range tt.expected {
+				finding := analysis.Findings[i]
+				if finding.Code != expected.Code || finding.Severity != expected.Severity || finding.Attribute != expected.Attribute {
+					t.Errorf("Findings[%d] = %+v, want code %s, severity %s, attribute %q", i, finding, expected.Code, expected.Severity, expected.Attribute)
+				}
+				if finding.Message == "" {
+					t.Errorf("Findings[%d] has no message", i)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzeResource_UnsupportedStatus(t *testing.T) {
+	analysis := AnalyzeResource(parser.TerraformResource{Mode: "managed", Type: "aws_s3_bucket", Name: "logs"})
+
+	if analysis.Status != StatusUnsupported || analysis.Architecture != ArchitectureUnknown {
+		t.Errorf("Status = %v, Architecture = %v, want unsupported, unknown", analysis.Status, analysis.Architecture)
+	}
+}
+
+// getFindingCodes lists the codes of an analysis' findings in order.
+func getFindingCodes(analysis ARM64Analysis) []FindingCode {
+	var codes []FindingCode
+	for _, finding := range analysis.Findings {
+		codes = append(codes, finding.Code)
+	}
+	return codes
+}
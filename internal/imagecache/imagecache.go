@@ -0,0 +1,319 @@
+package imagecache
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Cache is an offline stand-in for a container registry. It answers which
+// architectures an image reference was published for, using a local directory
+// that may contain:
+//
+//   - OCI image layouts (directories with an oci-layout file), matched on the
+//     org.opencontainers.image.ref.name annotation
+//   - docker save tarballs (*.tar), matched on RepoTags
+//   - manifest lists saved as JSON (for example from `docker manifest inspect`)
+//     under manifests/<registry>/<repository>/<tag>.json
+type Cache struct {
+	images map[string][]string
+}
+
+const refNameAnnotation = "org.opencontainers.image.ref.name"
+
+// Open indexes every image found under dir.
+func Open(dir string) (*Cache, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat image cache: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("image cache %s is not a directory", dir)
+	}
+
+	cache := &Cache{images: make(map[string][]string)}
+	manifestsDir := filepath.Join(dir, "manifests")
+
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if _, err := os.Stat(filepath.Join(path, "oci-layout")); err == nil {
+				if err := cache.loadOCILayout(path); err != nil {
+					return err
+				}
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		switch {
+		case strings.HasSuffix(path, ".tar"):
+			return cache.loadDockerArchive(path)
+		case strings.HasSuffix(path, ".json") && strings.HasPrefix(path, manifestsDir+string(filepath.Separator)):
+			return cache.loadManifestList(manifestsDir, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image cache: %w", err)
+	}
+	return cache, nil
+}
+
+// Architectures returns the architectures available for the image reference
+// and whether the image was found in the cache at all.
+func (c *Cache) Architectures(ref string) ([]string, bool) {
+	if c == nil {
+		return nil, false
+	}
+	architectures, found := c.images[NormalizeReference(ref)]
+	return architectures, found
+}
+
+// NormalizeReference expands short Docker Hub references so that "nginx",
+// "library/nginx:latest" and "docker.io/library/nginx" compare equal.
+func NormalizeReference(ref string) string {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return ""
+	}
+
+	name, suffix := ref, ":latest"
+	if i := strings.Index(ref, "@"); i >= 0 {
+		name, suffix = ref[:i], ref[i:]
+	} else if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		name, suffix = ref[:i], ref[i:]
+	}
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) == 1 || !(strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost") {
+		if len(parts) == 1 {
+			name = "library/" + name
+		}
+		name = "docker.io/" + name
+	}
+	return name + suffix
+}
+
+// add records the architectures an image was published for. An empty
+// architecture stands for a manifest without platform information; unless
+// another manifest already shows an arm64 variant, such images are left out
+// so lookups report them as unknown rather than x86-only. "unknown" entries,
+// such as build attestations, are ignored.
+func (c *Cache) add(ref string, architectures ...string) {
+	key := NormalizeReference(ref)
+	if key == "" {
+		return
+	}
+	if slices.Contains(architectures, "") && !slices.Contains(architectures, "arm64") {
+		return
+	}
+	for _, arch := range architectures {
+		if arch == "" || arch == "unknown" || slices.Contains(c.images[key], arch) {
+			continue
+		}
+		c.images[key] = append(c.images[key], arch)
+	}
+}
+
+type platform struct {
+	Architecture string `json:"architecture"`
+	OS           string `json:"os"`
+}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Platform    *platform         `json:"platform,omitempty"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type index struct {
+	MediaType string       `json:"mediaType"`
+	Manifests []descriptor `json:"manifests"`
+	Config    *descriptor  `json:"config,omitempty"`
+}
+
+func (c *Cache) loadOCILayout(dir string) error {
+	data, err := os.ReadFile(filepath.Join(dir, "index.json"))
+	if err != nil {
+		return fmt.Errorf("failed to read OCI layout %s: %w", dir, err)
+	}
+
+	var idx index
+	if err := json.Unmarshal(data, &idx); err != nil {
+		return fmt.Errorf("failed to parse OCI layout %s: %w", dir, err)
+	}
+
+	readBlob := func(digest string) ([]byte, error) {
+		algorithm, hex, ok := strings.Cut(digest, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid digest %q", digest)
+		}
+		return os.ReadFile(filepath.Join(dir, "blobs", algorithm, hex))
+	}
+
+	for _, manifest := range idx.Manifests {
+		ref := manifest.Annotations[refNameAnnotation]
+		if ref == "" {
+			continue
+		}
+		architectures, err := descriptorArchitectures(manifest, readBlob)
+		if err != nil {
+			return fmt.Errorf("failed to read %s in %s: %w", ref, dir, err)
+		}
+		c.add(ref, architectures...)
+	}
+	return nil
+}
+
+// descriptorArchitectures resolves a descriptor to the architectures it
+// covers, following image indexes and image configs as needed.
+func descriptorArchitectures(desc descriptor, readBlob func(string) ([]byte, error)) ([]string, error) {
+	if desc.Platform != nil {
+		return []string{desc.Platform.Architecture}, nil
+	}
+
+	data, err := readBlob(desc.Digest)
+	if err != nil {
+		return nil, err
+	}
+	var content index
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+
+	if content.Config != nil {
+		configData, err := readBlob(content.Config.Digest)
+		if err != nil {
+			return nil, err
+		}
+		var config platform
+		if err := json.Unmarshal(configData, &config); err != nil {
+			return nil, err
+		}
+		return []string{config.Architecture}, nil
+	}
+
+	var architectures []string
+	for _, manifest := range content.Manifests {
+		nested, err := descriptorArchitectures(manifest, readBlob)
+		if err != nil {
+			return nil, err
+		}
+		architectures = append(architectures, nested...)
+	}
+	return architectures, nil
+}
+
+type dockerArchiveManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+}
+
+func (c *Cache) loadDockerArchive(path string) error {
+	files, err := readTarJSON(path)
+	if err != nil {
+		return fmt.Errorf("failed to read docker archive %s: %w", path, err)
+	}
+
+	data, exists := files["manifest.json"]
+	if !exists {
+		return nil
+	}
+	var manifests []dockerArchiveManifest
+	if err := json.Unmarshal(data, &manifests); err != nil {
+		return fmt.Errorf("failed to parse manifest.json in %s: %w", path, err)
+	}
+
+	for _, manifest := range manifests {
+		var config platform
+		if configData, exists := files[manifest.Config]; exists {
+			if err := json.Unmarshal(configData, &config); err != nil {
+				return fmt.Errorf("failed to parse %s in %s: %w", manifest.Config, path, err)
+			}
+		}
+		for _, tag := range manifest.RepoTags {
+			c.add(tag, config.Architecture)
+		}
+	}
+	return nil
+}
+
+// readTarJSON returns the metadata files of a docker save archive, skipping
+// layer tarballs so large archives are not held in memory.
+func readTarJSON(path string) (map[string][]byte, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	files := make(map[string][]byte)
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if header.Typeflag != tar.TypeReg || header.Size > 1<<20 || strings.HasSuffix(header.Name, ".tar") {
+			continue
+		}
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return nil, err
+		}
+		files[strings.TrimPrefix(header.Name, "./")] = data
+	}
+	return files, nil
+}
+
+func (c *Cache) loadManifestList(manifestsDir, path string) error {
+	rel, err := filepath.Rel(manifestsDir, path)
+	if err != nil {
+		return err
+	}
+	rel = filepath.ToSlash(strings.TrimSuffix(rel, ".json"))
+	repository, tag := filepath.Dir(rel), filepath.Base(rel)
+	if repository == "." {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read manifest list %s: %w", path, err)
+	}
+	var list index
+	if err := json.Unmarshal(data, &list); err != nil {
+		return fmt.Errorf("failed to parse manifest list %s: %w", path, err)
+	}
+
+	separator := ":"
+	if strings.HasPrefix(tag, "sha256:") {
+		separator = "@"
+	}
+	ref := repository + separator + tag
+
+	var architectures []string
+	for _, manifest := range list.Manifests {
+		if manifest.Platform == nil {
+			architectures = append(architectures, "")
+			continue
+		}
+		architectures = append(architectures, manifest.Platform.Architecture)
+	}
+	c.add(ref, architectures...)
+	return nil
+}
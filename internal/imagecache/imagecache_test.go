@@ -0,0 +1,161 @@
+package imagecache
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeReference(t *testing.T) {
+	tests := []struct {
+		ref      string
+		expected string
+	}{
+		{"nginx", "docker.io/library/nginx:latest"},
+		{"nginx:1.25", "docker.io/library/nginx:1.25"},
+		{"library/nginx:1.25", "docker.io/library/nginx:1.25"},
+		{"bitnami/redis:7", "docker.io/bitnami/redis:7"},
+		{"localhost:5000/app", "localhost:5000/app:latest"},
+		{"123456789012.dkr.ecr.us-east-1.amazonaws.com/app:v1", "123456789012.dkr.ecr.us-east-1.amazonaws.com/app:v1"},
+		{"public.ecr.aws/nginx/nginx@sha256:abc", "public.ecr.aws/nginx/nginx@sha256:abc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			if result := NormalizeReference(tt.ref); result != tt.expected {
+				t.Errorf("NormalizeReference(%q) = %q, want %q", tt.ref, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	// OCI layout holding a multi-arch index for app:v1
+	layout := filepath.Join(dir, "app-layout")
+	writeFile(t, filepath.Join(layout, "oci-layout"), `{"imageLayoutVersion":"1.0.0"}`)
+	writeFile(t, filepath.Join(layout, "index.json"), `{
+		"schemaVersion": 2,
+		"manifests": [{
+			"mediaType": "application/vnd.oci.image.index.v1+json",
+			"digest": "sha256:aaaa",
+			"annotations": {"org.opencontainers.image.ref.name": "registry.example.com/app:v1"}
+		}]
+	}`)
+	writeFile(t, filepath.Join(layout, "blobs", "sha256", "aaaa"), `{
+		"schemaVersion": 2,
+		"manifests": [
+			{"digest": "sha256:bbbb", "platform": {"architecture": "amd64", "os": "linux"}},
+			{"digest": "sha256:cccc", "platform": {"architecture": "arm64", "os": "linux"}},
+			{"digest": "sha256:dddd", "platform": {"architecture": "unknown", "os": "unknown"}}
+		]
+	}`)
+
+	// docker save tarball for an amd64-only image
+	archive, err := os.Create(filepath.Join(dir, "legacy.tar"))
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	tw := tar.NewWriter(archive)
+	writeTarFile(t, tw, "manifest.json", `[{"Config":"cfg.json","RepoTags":["legacy/worker:2.0"],"Layers":[]}]`)
+	writeTarFile(t, tw, "cfg.json", `{"architecture":"amd64","os":"linux"}`)
+	tw.Close()
+	archive.Close()
+
+	// manifest list saved from docker manifest inspect
+	writeFile(t, filepath.Join(dir, "manifests", "docker.io", "library", "nginx", "1.25.json"), `{
+		"mediaType": "application/vnd.docker.distribution.manifest.list.v2+json",
+		"manifests": [
+			{"digest": "sha256:1111", "platform": {"architecture": "amd64", "os": "linux"}},
+			{"digest": "sha256:2222", "platform": {"architecture": "arm64", "os": "linux", "variant": "v8"}}
+		]
+	}`)
+
+	// manifest lists and archives without platform information
+	writeFile(t, filepath.Join(dir, "manifests", "registry.example.com", "bare", "1.0.json"), `{
+		"manifests": [{"digest": "sha256:3333"}]
+	}`)
+	writeFile(t, filepath.Join(dir, "manifests", "registry.example.com", "partial", "1.0.json"), `{
+		"manifests": [
+			{"digest": "sha256:4444"},
+			{"digest": "sha256:5555", "platform": {"architecture": "arm64", "os": "linux"}}
+		]
+	}`)
+	unlabelled, err := os.Create(filepath.Join(dir, "unlabelled.tar"))
+	if err != nil {
+		t.Fatalf("Failed to create archive: %v", err)
+	}
+	tw = tar.NewWriter(unlabelled)
+	writeTarFile(t, tw, "manifest.json", `[{"Config":"cfg.json","RepoTags":["unlabelled/worker:1.0"],"Layers":[]}]`)
+	writeTarFile(t, tw, "cfg.json", `{"os":"linux"}`)
+	tw.Close()
+	unlabelled.Close()
+
+	cache, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	tests := []struct {
+		ref           string
+		expectFound   bool
+		expectArm64   bool
+		expectArchLen int
+	}{
+		{"registry.example.com/app:v1", true, true, 2},
+		{"legacy/worker:2.0", true, false, 1},
+		{"docker.io/legacy/worker:2.0", true, false, 1},
+		{"nginx:1.25", true, true, 2},
+		{"nginx:1.26", false, false, 0},
+		{"registry.example.com/bare:1.0", false, false, 0},
+		{"registry.example.com/partial:1.0", true, true, 1},
+		{"unlabelled/worker:1.0", false, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			architectures, found := cache.Architectures(tt.ref)
+			if found != tt.expectFound {
+				t.Errorf("Architectures(%q) found = %v, want %v", tt.ref, found, tt.expectFound)
+			}
+			if len(architectures) != tt.expectArchLen {
+				t.Errorf("Architectures(%q) = %v, want %d entries", tt.ref, architectures, tt.expectArchLen)
+			}
+			if slices.Contains(architectures, "arm64") != tt.expectArm64 {
+				t.Errorf("Architectures(%q) = %v, arm64 expected %v", tt.ref, architectures, tt.expectArm64)
+			}
+		})
+	}
+}
+
+func TestOpen_NotADirectory(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	writeFile(t, file, "")
+
+	if _, err := Open(file); err == nil {
+		t.Error("Open() expected error for a regular file")
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+}
+
+func writeTarFile(t *testing.T, tw *tar.Writer, name, content string) {
+	t.Helper()
+	if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+		t.Fatalf("Failed to write tar header: %v", err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		t.Fatalf("Failed to write tar content: %v", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strings"
 )
 
 type TerraformState struct {
@@ -33,6 +34,20 @@ func (r *TerraformResource) GetFullAddress() string {
 	return fmt.Sprintf("%s.%s", r.Type, r.Name)
 }
 
+// GetProviderName returns the provider type from the provider address, e.g.
+// "aws" for provider["registry.terraform.io/hashicorp/aws"].
+func (r *TerraformResource) GetProviderName() string {
+	address, found := strings.CutPrefix(r.Provider, "provider[\"")
+	if !found {
+		// Terraform 0.12 style addresses: provider.aws or provider.aws.alias
+		name := strings.TrimPrefix(r.Provider, "provider.")
+		name, _, _ = strings.Cut(name, ".")
+		return name
+	}
+	address, _, _ = strings.Cut(address, "\"]")
+	return address[strings.LastIndex(address, "/")+1:]
+}
+
 func ParseStateFile(filename string) (*TerraformState, error) {
 	if filename == "" {
 		return nil, fmt.Errorf("filename cannot be empty")
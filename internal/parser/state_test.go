@@ -50,6 +50,29 @@ func TestTerraformResource_GetFullAddress(t *testing.T) {
 	}
 }
 
+func TestTerraformResource_GetProviderName(t *testing.T) {
+	tests := []struct {
+		provider string
+		expected string
+	}{
+		{`provider["registry.terraform.io/hashicorp/aws"]`, "aws"},
+		{`provider["registry.terraform.io/hashicorp/aws"].us_east_1`, "aws"},
+		{`provider["registry.terraform.io/hashicorp/google"]`, "google"},
+		{"provider.azurerm", "azurerm"},
+		{"provider.aws.west", "aws"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider, func(t *testing.T) {
+			resource := TerraformResource{Provider: tt.provider}
+			if result := resource.GetProviderName(); result != tt.expected {
+				t.Errorf("GetProviderName() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParseStateFile(t *testing.T) {
 	tests := []struct {
 		name        string
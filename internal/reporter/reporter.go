@@ -16,10 +16,20 @@ func New() *Reporter {
 func (r *Reporter) PrintAnalysis(analysis analyzer.ARM64Analysis) {
 	fmt.Printf("Resource: %s\n", analysis.FullAddress)
 	fmt.Printf("  Current Architecture: %s\n", analysis.CurrentArch)
+	fmt.Printf("  Status: %s\n", analysis.Status)
 	fmt.Printf("  ARM64 Compatible: %v\n", analysis.ARM64Compatible)
 	if analysis.ARM64Compatible && analysis.RecommendedArch != "" {
 		fmt.Printf("  Recommended: %s\n", analysis.RecommendedArch)
 	}
+	if analysis.RollUp {
+		fmt.Printf("  Roll-up: summarizes resources counted on their own\n")
+	}
+	if analysis.Effort > 0 {
+		fmt.Printf("  Effort: %d/5, estimated savings: ~%.0f%%\n", analysis.Effort, analysis.EstimatedSavingsPercent)
+	}
+	if analysis.Confidence != "" {
+		fmt.Printf("  Confidence: %s\n", analysis.Confidence)
+	}
 	fmt.Printf("  Notes: %s\n", analysis.Notes)
 	fmt.Println()
 }
@@ -36,6 +46,45 @@ func (r *Reporter) PrintSummary(totalAnalyzed, arm64Compatible, nonArm64Compatib
 	}
 }
 
+// PrintNotApplicable reports resources excluded from the summary percentages
+// because they can never run on ARM64, such as Windows workloads or
+// serverless capacity managed by AWS.
+func (r *Reporter) PrintNotApplicable(count int) {
+	fmt.Printf("  Resources not applicable for ARM64: %d\n", count)
+}
+
+// PrintStatusCounts breaks the analyzed resources down by status, including
+// the blocked, misconfigured and unknown ones the totals above leave out.
+func (r *Reporter) PrintStatusCounts(counts map[analyzer.Status]int) {
+	fmt.Printf("  Resources by status:\n")
+	for _, status := range []analyzer.Status{
+		analyzer.StatusAlreadyARM64,
+		analyzer.StatusMigratable,
+		analyzer.StatusPartiallyMigrated,
+		analyzer.StatusBlocked,
+		analyzer.StatusMisconfigured,
+		analyzer.StatusUnknown,
+		analyzer.StatusNotApplicable,
+	} {
+		if counts[status] > 0 {
+			fmt.Printf("    %s: %d\n", status, counts[status])
+		}
+	}
+}
+
+// PrintOpportunities lists the resources that can migrate in the order given,
+// which the caller sorts by savings per effort.
+func (r *Reporter) PrintOpportunities(opportunities []analyzer.ARM64Analysis) {
+	if len(opportunities) == 0 {
+		return
+	}
+	fmt.Printf("  Migration opportunities by savings per effort:\n")
+	for i, analysis := range opportunities {
+		fmt.Printf("    %d. %s: ~%.0f%% savings, effort %d/5, %s confidence\n",
+			i+1, analysis.FullAddress, analysis.EstimatedSavingsPercent, analysis.Effort, analysis.Confidence)
+	}
+}
+
 func (r *Reporter) PrintHeader(resourceCount int) {
 	fmt.Printf("Found %d resources\n", resourceCount)
 	fmt.Println(strings.Repeat("=", 80))
@@ -62,6 +62,23 @@ func TestReporter_PrintAnalysis(t *testing.T) {
 				"Notes: Instance type not available in ARM64",
 			},
 		},
+		{
+			name: "Roll-up of member resources",
+			analysis: analyzer.ARM64Analysis{
+				ResourceType:    "aws_rds_cluster",
+				ResourceName:    "main",
+				FullAddress:     "aws_rds_cluster.main",
+				CurrentArch:     "X86_64",
+				ARM64Compatible: true,
+				RecommendedArch: "ARM64",
+				RollUp:          true,
+				Notes:           "Can migrate cluster instances",
+			},
+			expected: []string{
+				"Resource: aws_rds_cluster.main",
+				"Roll-up: summarizes resources counted on their own",
+			},
+		},
 		{
 			name: "Already using ARM64",
 			analysis: analyzer.ARM64Analysis{
@@ -176,6 +193,56 @@ func TestReporter_PrintSummary(t *testing.T) {
 	}
 }
 
+func TestReporter_PrintNotApplicable(t *testing.T) {
+	output := captureOutput(func() {
+		reporter := New()
+		reporter.PrintNotApplicable(2)
+	})
+
+	expected := "Resources not applicable for ARM64: 2"
+	if !strings.Contains(output, expected) {
+		t.Errorf("PrintNotApplicable() output missing expected string %q\nGot: %s", expected, output)
+	}
+}
+
+func TestReporter_PrintStatusCounts(t *testing.T) {
+	output := captureOutput(func() {
+		reporter := New()
+		reporter.PrintStatusCounts(map[analyzer.Status]int{
+			analyzer.StatusMigratable: 3,
+			analyzer.StatusBlocked:    1,
+		})
+	})
+
+	for _, expected := range []string{"migratable: 3", "blocked: 1"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("PrintStatusCounts() output missing expected string %q\nGot: %s", expected, output)
+		}
+	}
+	if strings.Contains(output, "unknown") {
+		t.Errorf("PrintStatusCounts() should skip statuses without resources\nGot: %s", output)
+	}
+}
+
+func TestReporter_PrintOpportunities(t *testing.T) {
+	output := captureOutput(func() {
+		reporter := New()
+		reporter.PrintOpportunities([]analyzer.ARM64Analysis{
+			{FullAddress: "aws_lambda_function.api", Effort: 1, Confidence: analyzer.ConfidenceHigh, EstimatedSavingsPercent: 20},
+			{FullAddress: "aws_instance.web", Effort: 4, Confidence: analyzer.ConfidenceMedium, EstimatedSavingsPercent: 20},
+		})
+	})
+
+	for _, expected := range []string{
+		"1. aws_lambda_function.api: ~20% savings, effort 1/5, high confidence",
+		"2. aws_instance.web: ~20% savings, effort 4/5, medium confidence",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("PrintOpportunities() output missing expected string %q\nGot: %s", expected, output)
+		}
+	}
+}
+
 func TestReporter_PrintHeader(t *testing.T) {
 	tests := []struct {
 		name          string